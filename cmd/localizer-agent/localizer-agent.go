@@ -0,0 +1,87 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file is the entrypoint for the localizer-agent CLI
+// command for localizer.
+// Managed: true
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	oapp "github.com/getoutreach/gobox/pkg/app"
+	gcli "github.com/getoutreach/gobox/pkg/cli"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	// Place any extra imports for your startup code here
+	// <<Stencil::Block(imports)>>
+	// <</Stencil::Block>>
+)
+
+// <<Stencil::Block(global)>>
+
+// <</Stencil::Block>>
+
+// main is the entrypoint for the localizer-agent CLI.
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	log := logrus.New()
+
+	// <<Stencil::Block(init)>>
+
+	// <</Stencil::Block>>
+
+	app := cli.Command{
+		Version:               oapp.Version,
+		Name:                  "localizer-agent",
+		EnableShellCompletion: true,
+		// <<Stencil::Block(app)>>
+		Usage: "Agent ran inside of the pods created by localizer expose",
+		// <</Stencil::Block>>
+	}
+	app.Flags = []cli.Flag{
+		// <<Stencil::Block(flags)>>
+		&cli.StringFlag{
+			Name:    "log-level",
+			Usage:   "Set the log level. Valid values are: debug",
+			Sources: cli.EnvVars("LOG_LEVEL"),
+		},
+		// <</Stencil::Block>>
+	}
+	app.Commands = []*cli.Command{
+		// <<Stencil::Block(commands)>>
+		NewRouteCommand(log),
+		NewTunnelCommand(log),
+		NewVPNCommand(log),
+		// <</Stencil::Block>>
+	}
+
+	// <<Stencil::Block(postApp)>>
+	log.Formatter = &logrus.JSONFormatter{}
+
+	app.Before = func(ctx context.Context, c *cli.Command) (context.Context, error) {
+		sigC := make(chan os.Signal, 1)
+		signal.Notify(sigC, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			sig := <-sigC
+			log.WithField("signal", sig.String()).Info("shutting down")
+			cancel()
+		}()
+
+		if strings.EqualFold(c.String("log-level"), "debug") {
+			log.SetLevel(logrus.DebugLevel)
+		}
+
+		return ctx, nil
+	}
+	// <</Stencil::Block>>
+
+	// Insert global flags, tracing, updating and start the application.
+	gcli.RunV3(ctx, cancel, &app, &gcli.Config{
+		Logger: log,
+	})
+}
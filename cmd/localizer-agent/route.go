@@ -0,0 +1,105 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package main.
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/getoutreach/localizer/internal/router"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/sync/errgroup"
+)
+
+// routePort is a port the route command listens on
+type routePort struct {
+	// listen is the port to listen on, and the port requests are sent
+	// to on the origin host
+	listen uint64
+
+	// local is the port, on localhost, that requests matching the rules
+	// are sent to. This is the port the reverse tunnel listens on.
+	local uint64
+}
+
+// parseRoutePort parses a port in the format listen:local
+func parseRoutePort(s string) (routePort, error) {
+	listen, local, ok := strings.Cut(s, ":")
+	if !ok {
+		return routePort{}, fmt.Errorf("invalid port %q, expected listen:local", s)
+	}
+
+	var p routePort
+	var err error
+	if p.listen, err = strconv.ParseUint(listen, 10, 16); err != nil {
+		return routePort{}, errors.Wrapf(err, "invalid port %q", s)
+	}
+	if p.local, err = strconv.ParseUint(local, 10, 16); err != nil {
+		return routePort{}, errors.Wrapf(err, "invalid port %q", s)
+	}
+	return p, nil
+}
+
+func NewRouteCommand(log logrus.FieldLogger) *cli.Command {
+	return &cli.Command{
+		Name:        "route",
+		Description: "Route HTTP requests matching headers to a reverse tunnel, and everything else to the original workload",
+		Usage:       "route --origin-host <host> --port <listen:local> --header <name=value>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "origin-host",
+				Usage:    "Host of the original workload, usually a service, that non-matching requests are sent to",
+				Required: true,
+			},
+			&cli.StringSliceFlag{
+				Name:     "port",
+				Usage:    "Port to route in the format listen:local, matching requests are sent to localhost:<local> and everything else to <origin-host>:<listen>",
+				Required: true,
+			},
+			&cli.StringSliceFlag{
+				Name:     "header",
+				Usage:    "Header that requests must have to be sent to the reverse tunnel, in the format name=value or name, or N% to send a percentage of requests",
+				Required: true,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			rules := make([]router.Rule, 0, len(c.StringSlice("header")))
+			for _, h := range c.StringSlice("header") {
+				rule, err := router.ParseRule(h)
+				if err != nil {
+					return err
+				}
+				rules = append(rules, rule)
+			}
+
+			g, ctx := errgroup.WithContext(ctx)
+			for _, portStr := range c.StringSlice("port") {
+				port, err := parseRoutePort(portStr)
+				if err != nil {
+					return err
+				}
+
+				listenAddr := net.JoinHostPort("", strconv.FormatUint(port.listen, 10))
+				localAddr := net.JoinHostPort("127.0.0.1", strconv.FormatUint(port.local, 10))
+				originAddr := net.JoinHostPort(c.String("origin-host"), strconv.FormatUint(port.listen, 10))
+
+				l, err := net.Listen("tcp", listenAddr)
+				if err != nil {
+					return errors.Wrapf(err, "failed to listen on %s", listenAddr)
+				}
+
+				r := router.New(log.WithField("port", port.listen), rules, localAddr, originAddr)
+				log.Infof("routing requests on %s matching %v to %s, everything else to %s", listenAddr, rules, localAddr, originAddr)
+				g.Go(func() error { return r.Serve(ctx, l) })
+			}
+
+			return g.Wait()
+		},
+	}
+}
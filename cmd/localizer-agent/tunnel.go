@@ -0,0 +1,53 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package main.
+package main
+
+import (
+	"context"
+	"net"
+
+	"github.com/getoutreach/localizer/internal/agent"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+)
+
+func NewTunnelCommand(log logrus.FieldLogger) *cli.Command {
+	return &cli.Command{
+		Name:        "tunnel",
+		Description: "Serve reverse tunnels for localizer, streaming connections on the requested ports back to it",
+		Usage:       "tunnel [--listen :2222] [--keys-dir /etc/localizer/keys]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "listen",
+				Usage: "Address to accept tunnel sessions on",
+				Value: ":2222",
+			},
+			&cli.StringFlag{
+				Name:  "keys-dir",
+				Usage: "Directory containing " + agent.HostKeyFile + " and " + agent.AuthorizedKeysFile,
+				Value: "/etc/localizer/keys",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			hostKey, authorized, err := agent.LoadKeys(c.String("keys-dir"))
+			if err != nil {
+				return err
+			}
+
+			srv, err := agent.NewServer(log, hostKey, authorized)
+			if err != nil {
+				return err
+			}
+
+			l, err := net.Listen("tcp", c.String("listen"))
+			if err != nil {
+				return errors.Wrapf(err, "failed to listen on %s", c.String("listen"))
+			}
+
+			log.Infof("accepting tunnel sessions on %s", l.Addr())
+			return srv.Serve(ctx, l)
+		},
+	}
+}
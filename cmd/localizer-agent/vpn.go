@@ -0,0 +1,83 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package main.
+package main
+
+import (
+	"context"
+	"net"
+	"net/netip"
+
+	"github.com/getoutreach/localizer/internal/agent"
+	"github.com/getoutreach/localizer/internal/tun"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+)
+
+func NewVPNCommand(log logrus.FieldLogger) *cli.Command {
+	return &cli.Command{
+		Name:        "vpn",
+		Description: "Serve the VPN of localizer, forwarding the packets it sends into the cluster's network",
+		Usage:       "vpn [--listen :2222] [--keys-dir /etc/localizer/keys]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "listen",
+				Usage: "Address to accept VPN sessions on",
+				Value: ":2222",
+			},
+			&cli.StringFlag{
+				Name:  "keys-dir",
+				Usage: "Directory containing " + agent.HostKeyFile + " and " + agent.AuthorizedKeysFile,
+				Value: "/etc/localizer/keys",
+			},
+			&cli.StringFlag{
+				Name:  "device",
+				Usage: "Name of the TUN device to create, %d is replaced with a number",
+				Value: "localizer%d",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			hostKey, authorized, err := agent.LoadKeys(c.String("keys-dir"))
+			if err != nil {
+				return err
+			}
+
+			dev, err := tun.Open(c.String("device"))
+			if err != nil {
+				return err
+			}
+			defer dev.Close()
+
+			if err := dev.Configure(tun.AgentAddress, tun.ClientAddress, nil); err != nil {
+				return errors.Wrap(err, "failed to configure tun device")
+			}
+
+			// packets from localizer are sent from the pod's address, so
+			// replies are routed back to the agent
+			source := netip.PrefixFrom(tun.ClientAddress, tun.ClientAddress.BitLen())
+			if err := dev.EnableNAT(source); err != nil {
+				return err
+			}
+			defer func() {
+				if err := dev.DisableNAT(source); err != nil {
+					log.WithError(err).Warn("failed to remove nat rule")
+				}
+			}()
+
+			srv, err := agent.NewServer(log, hostKey, authorized)
+			if err != nil {
+				return err
+			}
+			srv.EnableVPN(dev)
+
+			l, err := net.Listen("tcp", c.String("listen"))
+			if err != nil {
+				return errors.Wrapf(err, "failed to listen on %s", c.String("listen"))
+			}
+
+			log.Infof("accepting vpn sessions on %s, forwarding packets from %s", l.Addr(), dev.Name())
+			return srv.Serve(ctx, l)
+		},
+	}
+}
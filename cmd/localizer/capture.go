@@ -0,0 +1,225 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package main.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/internal/capture"
+	"github.com/getoutreach/localizer/pkg/localizer"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// defaultCaptureSize is the default amount of data captured before
+// stopping
+const defaultCaptureSize = 100 * 1024 * 1024
+
+func NewCaptureCommand(log logrus.FieldLogger) *cli.Command { //nolint:funlen // Why: there are no reusable parts to extract
+	return &cli.Command{
+		Name:        "capture",
+		Description: "record the connections tunneled for a service, by port-forwards and exposes, until interrupted",
+		Usage:       "capture [--port port] [-o file] namespace/service",
+		Commands:    []*cli.Command{newCaptureReplayCommand(log)},
+		Flags: []cli.Flag{
+			&cli.Uint16Flag{
+				Name:  "port",
+				Usage: "Service port to capture, defaults to every port",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "File to write the capture to, - writes to stdout",
+				Value:   "-",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Format of the capture, one of: jsonl, pcapng. Defaults to the output's extension, or jsonl",
+			},
+			&cli.Uint64Flag{
+				Name:  "max-size",
+				Usage: "Bytes of data to capture before stopping, 0 means there's no limit",
+				Value: defaultCaptureSize,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			split := strings.Split(c.Args().First(), "/")
+			if len(split) != 2 {
+				return fmt.Errorf("invalid service, expected namespace/name")
+			}
+
+			output := c.String("output")
+			format := c.String("format")
+			if format == "" {
+				format = capture.FormatFromPath(output)
+			}
+
+			if !localizer.IsRunning() {
+				return fmt.Errorf("localizer daemon not running (run localizer by itself?)")
+			}
+
+			// nolint: staticcheck // Why: we are not upgrading to the new grpc API yet.
+			client, closer, err := localizer.Connect(ctx, grpc.WithBlock(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				return errors.Wrap(err, "failed to connect to localizer daemon")
+			}
+			defer closer()
+
+			// The file is written here, rather than by the daemon, so that
+			// it's owned by the user.
+			var out io.Writer = os.Stdout
+			if output != "-" {
+				f, err := os.Create(output)
+				if err != nil {
+					return errors.Wrap(err, "failed to create output file")
+				}
+				defer f.Close()
+				out = f
+			}
+
+			w, err := capture.NewWriter(out, format)
+			if err != nil {
+				return err
+			}
+
+			stream, err := client.Capture(ctx, &api.CaptureRequest{
+				Namespace: split[0],
+				Service:   split[1],
+				Port:      uint32(c.Uint16("port")),
+				MaxBytes:  c.Uint64("max-size"),
+			})
+			if err != nil {
+				return err
+			}
+
+			log.Infof("capturing %s, press Ctrl+C to stop", c.Args().First())
+
+			var events, dropped uint64
+			for {
+				e, err := stream.Recv()
+				if err != nil {
+					if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+						break
+					}
+					return err
+				}
+
+				events++
+				dropped = e.Dropped
+				if err := w.Write(captureEventFromAPI(e)); err != nil {
+					return err
+				}
+			}
+
+			log.Infof("captured %d events", events)
+			if dropped != 0 {
+				log.Warnf("dropped %d events that weren't written fast enough", dropped)
+			}
+			return nil
+		},
+	}
+}
+
+// newCaptureReplayCommand creates the command that replays the
+// connections of a JSONL capture
+func newCaptureReplayCommand(log logrus.FieldLogger) *cli.Command {
+	return &cli.Command{
+		Name:        "replay",
+		Description: "open the connections of a jsonl capture again, sending what their clients sent and comparing the responses",
+		Usage:       "capture replay [--target host:port] [--timing] file",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "target",
+				Usage: "Address to replay connections to, defaults to the address they were recorded with",
+			},
+			&cli.BoolFlag{
+				Name:  "timing",
+				Usage: "Send data with the delays it was recorded with, instead of as fast as possible",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.Args().Len() != 1 {
+				return fmt.Errorf("expected a capture file")
+			}
+			if capture.FormatFromPath(c.Args().First()) != "jsonl" {
+				return fmt.Errorf("only jsonl captures can be replayed")
+			}
+
+			f, err := os.Open(c.Args().First())
+			if err != nil {
+				return errors.Wrap(err, "failed to open capture")
+			}
+			defer f.Close()
+
+			results, err := capture.Replay(ctx, capture.NewJSONLReader(f), &capture.ReplayOptions{
+				Target: c.String("target"),
+				Timing: c.Bool("timing"),
+			})
+			if err != nil {
+				return err
+			}
+
+			mismatched := 0
+			for i := range results {
+				r := &results[i]
+				log := log.WithFields(logrus.Fields{"conn": r.Conn, "sent": r.Sent, "received": r.Received})
+				switch {
+				case r.Err != nil:
+					mismatched++
+					log.WithError(r.Err).Warn("connection failed")
+				case !r.Matched:
+					mismatched++
+					log.Warn("response differs from the capture")
+				default:
+					log.Info("response matches the capture")
+				}
+			}
+
+			log.Infof("replayed %d connections, %d didn't match", len(results), mismatched)
+			if mismatched != 0 {
+				return fmt.Errorf("%d connections didn't match the capture", mismatched)
+			}
+			return nil
+		},
+	}
+}
+
+// captureEventFromAPI converts an API capture event into a capture.Event
+func captureEventFromAPI(e *api.CaptureEvent) *capture.Event {
+	out := &capture.Event{
+		Time:   e.Time.AsTime(),
+		Conn:   e.Conn,
+		Client: e.Client,
+		Server: e.Server,
+		Data:   e.Data,
+	}
+
+	switch e.Type {
+	case api.CaptureEventType_CAPTURE_EVENT_TYPE_OPEN:
+		out.Type = capture.EventOpen
+	case api.CaptureEventType_CAPTURE_EVENT_TYPE_DATA:
+		out.Type = capture.EventData
+	case api.CaptureEventType_CAPTURE_EVENT_TYPE_CLOSE:
+		out.Type = capture.EventClose
+	}
+
+	switch e.Direction {
+	case api.CaptureDirection_CAPTURE_DIRECTION_CLIENT:
+		out.Direction = capture.DirectionClient
+	case api.CaptureDirection_CAPTURE_DIRECTION_SERVER:
+		out.Direction = capture.DirectionServer
+	}
+
+	return out
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package main.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/pkg/localizer"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// envOutputPort is the JSON representation of api.EnvPort
+type envOutputPort struct {
+	Name      string `json:"name,omitempty"`
+	Protocol  string `json:"protocol"`
+	Port      uint32 `json:"port"`
+	LocalPort uint32 `json:"localPort"`
+}
+
+// envOutputService is the JSON representation of api.EnvService
+type envOutputService struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Hostname  string            `json:"hostname"`
+	IP        string            `json:"ip,omitempty"`
+	IPv6      string            `json:"ipv6,omitempty"`
+	Ports     []envOutputPort   `json:"ports"`
+	Variables map[string]string `json:"variables"`
+}
+
+func NewEnvCommand(_ logrus.FieldLogger) *cli.Command {
+	return &cli.Command{
+		Name:        "env",
+		Description: "print connection information and Kubernetes style environment variables for tunneled services",
+		Usage:       "env [namespace/service|namespace...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format, one of: shell, dotenv, json",
+				Value: "shell",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			format := c.String("format")
+			switch format {
+			case "shell", "dotenv", "json":
+			default:
+				return fmt.Errorf("unknown format %q, expected one of: shell, dotenv, json", format)
+			}
+
+			if !localizer.IsRunning() {
+				return fmt.Errorf("localizer daemon not running (run localizer by itself?)")
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+
+			// nolint: staticcheck // Why: we are not upgrading to the new grpc API yet.
+			client, closer, err := localizer.Connect(ctx, grpc.WithBlock(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				return errors.Wrap(err, "failed to connect to localizer daemon")
+			}
+			defer closer()
+
+			resp, err := client.Env(ctx, &api.EnvRequest{Services: c.Args().Slice()})
+			if err != nil {
+				return err
+			}
+
+			if format == "json" {
+				return writeEnvJSON(os.Stdout, resp.Services)
+			}
+			return writeEnvVariables(os.Stdout, format, resp.Variables)
+		},
+	}
+}
+
+// writeEnvJSON writes the provided services as JSON to w
+func writeEnvJSON(w io.Writer, services []*api.EnvService) error {
+	out := make([]envOutputService, len(services))
+	for i, s := range services {
+		ports := make([]envOutputPort, len(s.Ports))
+		for j, p := range s.Ports {
+			ports[j] = envOutputPort{
+				Name:      p.Name,
+				Protocol:  p.Protocol,
+				Port:      p.Port,
+				LocalPort: p.LocalPort,
+			}
+		}
+
+		out[i] = envOutputService{
+			Namespace: s.Namespace,
+			Name:      s.Name,
+			Hostname:  s.Hostname,
+			IP:        s.Ip,
+			IPv6:      s.Ipv6,
+			Ports:     ports,
+			Variables: s.Variables,
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// writeEnvVariables writes the provided variables, sorted by name, in
+// either the shell or dotenv format to w
+func writeEnvVariables(w io.Writer, format string, vars map[string]string) error {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		var err error
+		if format == "dotenv" {
+			_, err = fmt.Fprintf(w, "%s=%s\n", k, dotenvQuote(vars[k]))
+		} else {
+			_, err = fmt.Fprintf(w, "export %s=%s\n", k, shellQuote(vars[k]))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// shellQuote quotes a string so that it can be safely used as a single
+// argument in a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// dotenvSafeValue matches values that don't need to be quoted in a
+// dotenv file.
+var dotenvSafeValue = regexp.MustCompile(`^[A-Za-z0-9_./:@\[\]-]*$`)
+
+// dotenvQuote quotes a string, if required, for use as a value in a
+// dotenv file.
+func dotenvQuote(s string) string {
+	if dotenvSafeValue.MatchString(s) {
+		return s
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
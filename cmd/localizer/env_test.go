@@ -0,0 +1,89 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package main.
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteEnvVariables(t *testing.T) {
+	vars := map[string]string{
+		"WEB_SERVICE_HOST": "127.0.0.2",
+		"WEB_PORT":         "tcp://127.0.0.2:80",
+		"QUOTED":           "it's \"a\"\nvalue",
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "dotenv",
+			want: `QUOTED="it's \"a\"\nvalue"` + "\n" +
+				"WEB_PORT=tcp://127.0.0.2:80\n" +
+				"WEB_SERVICE_HOST=127.0.0.2\n",
+		},
+		{
+			format: "shell",
+			want: `export QUOTED='it'\''s "a"` + "\nvalue'\n" +
+				"export WEB_PORT='tcp://127.0.0.2:80'\n" +
+				"export WEB_SERVICE_HOST='127.0.0.2'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeEnvVariables(&buf, tt.format, vars); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("writeEnvVariables() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteEnvJSON(t *testing.T) {
+	services := []*api.EnvService{{
+		Namespace: "default",
+		Name:      "web",
+		Hostname:  "web.default.svc.cluster.local",
+		Ip:        "127.0.0.2",
+		Ports:     []*api.EnvPort{{Name: "http", Protocol: "TCP", Port: 80, LocalPort: 8080}},
+		Variables: map[string]string{"WEB_SERVICE_HOST": "127.0.0.2"},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeEnvJSON(&buf, services); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[
+  {
+    "namespace": "default",
+    "name": "web",
+    "hostname": "web.default.svc.cluster.local",
+    "ip": "127.0.0.2",
+    "ports": [
+      {
+        "name": "http",
+        "protocol": "TCP",
+        "port": 80,
+        "localPort": 8080
+      }
+    ],
+    "variables": {
+      "WEB_SERVICE_HOST": "127.0.0.2"
+    }
+  }
+]
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("writeEnvJSON() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -17,50 +17,111 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/internal/expose"
 	"github.com/getoutreach/localizer/pkg/localizer"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func NewExposeCommand(log logrus.FieldLogger) *cli.Command {
 	return &cli.Command{
 		Name:        "expose",
 		Description: "Expose ports for a given service to Kubernetes",
-		Usage:       "expose <namespace/service>",
+		Usage:       "expose <namespace/service> | --create <namespace/service> --port <port> | --list | --list-sessions",
 		Flags: []cli.Flag{
 			&cli.StringSliceFlag{
 				Name:  "map",
-				Usage: "Map a local port to a remote port, i.e --map 80:8080 will bind what is normally :8080 to :80 locally",
+				Usage: "Map a local port to a remote port, i.e --map 80:8080 will bind what is normally :8080 to :80 locally. Use --map 8080=<target> to send a remote port to another local address instead, where target is a port, host:port or unix:///path/to/socket",
 			},
 			&cli.BoolFlag{
 				Name:  "stop",
 				Usage: "stop exposing a service",
 			},
+			&cli.DurationFlag{
+				Name:  "drain-timeout",
+				Usage: "How long to wait for connections to the local service to finish when the expose is stopped",
+				Value: expose.DefaultDrainTimeout,
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Take over the service if it's already exposed by someone else, restoring it to its original state first",
+			},
+			&cli.StringFlag{
+				Name:  "create",
+				Usage: "Create a service, in the format namespace/name, pointing at the local machine instead of exposing an existing one. It's deleted when the expose stops",
+			},
+			&cli.Uint16SliceFlag{
+				Name:  "port",
+				Usage: "Port of the service created by --create, can be repeated",
+			},
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "List the services exposed by this daemon and the health of their tunnels",
+			},
+			&cli.BoolFlag{
+				Name:  "list-sessions",
+				Usage: "List the expose sessions of every developer in the cluster",
+			},
+			&cli.BoolFlag{
+				Name:  "intercept",
+				Usage: "Add the local service alongside the existing replicas instead of scaling them down, only a share of the traffic will be sent locally",
+			},
+			&cli.StringSliceFlag{
+				Name:  "header",
+				Usage: "Only send HTTP/gRPC requests with the given header, in the format name=value or name, to the local service. Everything else keeps going to the existing replicas",
+			},
+			&cli.UintFlag{
+				Name:  "percent",
+				Usage: "Only send this percentage (1-100) of HTTP/gRPC requests, out of those matching --header if set, to the local service. Everything else keeps going to the existing replicas",
+			},
+			&cli.StringFlag{
+				Name:  "pod-config",
+				Usage: "YAML file overriding the daemon's options for the expose pod, see --expose-pod-config",
+			},
+			&cli.StringFlag{
+				Name:  "tunnel-image",
+				Usage: "Flavor of tunnel image to use, one of: openssh, minimal",
+			},
+			&cli.StringFlag{
+				Name:  "image",
+				Usage: "Override the image of the tunnel container, e.g. to use a mirror",
+			},
+			&cli.StringFlag{
+				Name:  "env-file",
+				Usage: "Write the environment of the replaced workload, including ConfigMap and Secret values, to a dotenv file",
+			},
+			&cli.StringFlag{
+				Name:  "config-dir",
+				Usage: "Write files mounted into the replaced workload from ConfigMaps and Secrets into a directory, i.e /etc/app/config.yaml is written to <dir>/etc/app/config.yaml",
+			},
+			&cli.StringFlag{
+				Name:  "container",
+				Usage: "Container of the replaced workload to copy configuration from (default: the first container)",
+			},
 		},
 		Action: func(ctx context.Context, c *cli.Command) error {
-			split := strings.Split(c.Args().First(), "/")
-			if len(split) != 2 {
-				return fmt.Errorf("invalid service, expected namespace/name")
-			}
-
-			serviceNamespace := split[0]
-			serviceName := split[1]
-
 			if !localizer.IsRunning() {
 				return fmt.Errorf("localizer daemon not running (run localizer by itself?)")
 			}
 
-			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			// the daemon waits for the expose pod to be scheduled, pull its
+			// image and for the tunnel to come up before responding
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 			defer cancel()
 
 			log.Info("connecting to localizer daemon")
@@ -72,6 +133,32 @@ func NewExposeCommand(log logrus.FieldLogger) *cli.Command {
 			}
 			defer closer()
 
+			switch {
+			case c.Bool("list"):
+				return listExposes(ctx, client)
+			case c.Bool("list-sessions"):
+				return listExposeSessions(ctx, client)
+			}
+
+			service := c.Args().First()
+			if c.String("create") != "" {
+				if service != "" {
+					return fmt.Errorf("--create and a service argument are mutually exclusive")
+				}
+				if len(c.Uint16Slice("port")) == 0 {
+					return fmt.Errorf("--create requires at least one --port")
+				}
+				service = c.String("create")
+			}
+
+			split := strings.Split(service, "/")
+			if len(split) != 2 {
+				return fmt.Errorf("invalid service, expected namespace/name")
+			}
+
+			serviceNamespace := split[0]
+			serviceName := split[1]
+
 			var stream api.LocalizerService_ExposeServiceClient
 			if c.Bool("stop") {
 				log.Info("sending stop expose request to daemon")
@@ -80,11 +167,52 @@ func NewExposeCommand(log logrus.FieldLogger) *cli.Command {
 					Service:   serviceName,
 				})
 			} else {
+				if (c.String("env-file") != "" || c.String("config-dir") != "") && c.String("create") != "" {
+					return fmt.Errorf("there is no workload to copy configuration from when using --create")
+				}
+				if c.String("env-file") != "" || c.String("config-dir") != "" {
+					if err := writeWorkloadConfig(ctx, log, client, c, serviceNamespace, serviceName); err != nil {
+						return err
+					}
+				}
+
+				route := len(c.StringSlice("header")) != 0 || c.IsSet("percent")
+				mode := api.ExposeMode_EXPOSE_MODE_REPLACE
+				switch {
+				case c.Bool("intercept") && route:
+					return fmt.Errorf("--intercept is mutually exclusive with --header and --percent")
+				case c.Bool("intercept"):
+					mode = api.ExposeMode_EXPOSE_MODE_INTERCEPT
+				case route:
+					if c.IsSet("percent") && (c.Uint("percent") < 1 || c.Uint("percent") > 100) {
+						return fmt.Errorf("--percent must be between 1 and 100")
+					}
+					mode = api.ExposeMode_EXPOSE_MODE_ROUTE
+				}
+
+				podOptions, err := getPodOptions(c)
+				if err != nil {
+					return err
+				}
+
+				ports := make([]uint32, 0, len(c.Uint16Slice("port")))
+				for _, port := range c.Uint16Slice("port") {
+					ports = append(ports, uint32(port))
+				}
+
 				log.Info("sending expose request to daemon")
 				stream, err = client.ExposeService(ctx, &api.ExposeServiceRequest{
-					PortMap:   c.StringSlice("map"),
-					Namespace: serviceNamespace,
-					Service:   serviceName,
+					PortMap:      c.StringSlice("map"),
+					Namespace:    serviceNamespace,
+					Service:      serviceName,
+					Mode:         mode,
+					Headers:      c.StringSlice("header"),
+					Percent:      uint32(c.Uint("percent")), //nolint:gosec // Why: validated above
+					PodOptions:   podOptions,
+					Force:        c.Bool("force"),
+					Create:       c.String("create") != "",
+					Ports:        ports,
+					DrainTimeout: durationpb.New(c.Duration("drain-timeout")),
 				})
 			}
 			if err != nil {
@@ -113,3 +241,189 @@ func NewExposeCommand(log logrus.FieldLogger) *cli.Command {
 		},
 	}
 }
+
+// writeWorkloadConfig copies the configuration of the workload being
+// replaced by an expose to the local filesystem, based on the --env-file
+// and --config-dir flags. This is done by the CLI, rather than the daemon,
+// so that the files are owned by the invoking user.
+func writeWorkloadConfig(ctx context.Context, log logrus.FieldLogger, client api.LocalizerServiceClient,
+	c *cli.Command, namespace, serviceName string) error {
+	envFile := c.String("env-file")
+	configDir := c.String("config-dir")
+
+	resp, err := client.WorkloadConfig(ctx, &api.WorkloadConfigRequest{
+		Namespace:    namespace,
+		Service:      serviceName,
+		Container:    c.String("container"),
+		IncludeFiles: configDir != "",
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get workload configuration")
+	}
+	log.Infof("copying configuration from container %q of %s", resp.Container, resp.Controller)
+
+	if envFile != "" {
+		var buf bytes.Buffer
+		if err := writeEnvVariables(&buf, "dotenv", resp.Env); err != nil {
+			return err
+		}
+
+		// The environment usually contains secrets, so don't make it
+		// readable by other users.
+		if err := writeFile(envFile, buf.Bytes(), 0o600); err != nil {
+			return errors.Wrap(err, "failed to write env file")
+		}
+		log.Infof("wrote %d environment variables to %s", len(resp.Env), envFile)
+	}
+
+	if configDir == "" {
+		return nil
+	}
+
+	for _, f := range resp.Files {
+		rel := strings.TrimPrefix(filepath.Clean(filepath.FromSlash(f.Path)), string(filepath.Separator))
+		if !filepath.IsLocal(rel) {
+			log.WithField("path", f.Path).Warn("skipping file with invalid path")
+			continue
+		}
+
+		// Like the environment, files from Secrets aren't made readable by
+		// other users, and neither are the directories they're in.
+		mode := os.FileMode(f.Mode) & os.ModePerm
+		if f.Secret {
+			mode &= 0o700
+		}
+
+		p := filepath.Join(configDir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			return errors.Wrapf(err, "failed to create directory for %s", f.Path)
+		}
+
+		if err := writeFile(p, f.Data, mode); err != nil {
+			return errors.Wrapf(err, "failed to write %s", f.Path)
+		}
+	}
+	log.Infof("wrote %d files to %s", len(resp.Files), configDir)
+
+	return nil
+}
+
+// writeFile writes data to path with the given mode. Unlike os.WriteFile
+// this also applies mode when path already exists, by writing to a
+// temporary file, which is only readable by us, and renaming it into
+// place.
+func writeFile(path string, data []byte, mode os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck // Why: it's already gone if it was renamed
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// getPodOptions returns the pod options for an expose from the
+// --pod-config file, with --tunnel-image and --image taking precedence
+func getPodOptions(c *cli.Command) (*api.ExposePodOptions, error) {
+	opts := &api.ExposePodOptions{}
+	if c.String("pod-config") != "" {
+		var err error
+		opts, err = loadPodOptions(c.String("pod-config"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.String("tunnel-image") != "" {
+		opts.TunnelImage = c.String("tunnel-image")
+	}
+	if c.String("image") != "" {
+		opts.Image = c.String("image")
+	}
+	return opts, nil
+}
+
+// listExposeSessions prints the expose sessions in the cluster as a table
+func listExposeSessions(ctx context.Context, client api.LocalizerServiceClient) error {
+	resp, err := client.ListExposeSessions(ctx, &api.Empty{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list expose sessions")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 10, 0, 3, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "NAMESPACE\tNAME\tOWNER\tMODE\tPORT(S)\tWORKLOADS\tAGE\tSTATUS\t\n")
+	for _, s := range resp.Sessions {
+		mode := strings.ToLower(strings.TrimPrefix(s.Mode.String(), "EXPOSE_MODE_"))
+		status := "Active"
+		if s.Expired {
+			status = "Expired"
+		}
+
+		objects := strings.Join(s.Objects, ",")
+		if objects == "" {
+			objects = "None"
+		}
+
+		fmt.Fprintf(w,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.Namespace, s.Service, s.Owner, mode, strings.Join(s.Ports, ","), objects,
+			time.Since(s.CreatedAt.AsTime()).Round(time.Second), status,
+		)
+	}
+
+	return nil
+}
+
+// listExposes prints the services exposed by the daemon as a table
+func listExposes(ctx context.Context, client api.LocalizerServiceClient) error {
+	resp, err := client.List(ctx, &api.ListRequest{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list exposed services")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 10, 0, 3, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "NAMESPACE\tNAME\tMODE\tSTATE\tPOD\tRECONNECTS\tTARGET(S)\tWORKLOADS\tLAST ERROR\t\n")
+	for _, e := range resp.Exposes {
+		mode := strings.ToLower(strings.TrimPrefix(e.Mode.String(), "EXPOSE_MODE_"))
+
+		targets := make([]string, len(e.Targets))
+		for i, t := range e.Targets {
+			targets[i] = fmt.Sprintf("%d->%s", t.RemotePort, t.Address)
+			if !t.Reachable {
+				targets[i] += " (unreachable)"
+			}
+		}
+
+		objects := strings.Join(e.Objects, ",")
+		if objects == "" {
+			objects = "None"
+		}
+
+		pod := e.Pod
+		if pod == "" {
+			pod = "None"
+		}
+
+		fmt.Fprintf(w,
+			"%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			e.Namespace, e.Name, mode, e.State, pod, e.Reconnects, strings.Join(targets, ","), objects, e.LastError,
+		)
+	}
+
+	return nil
+}
@@ -74,7 +74,14 @@ func NewListCommand(_ logrus.FieldLogger) *cli.Command {
 
 			for _, s := range resp.Services {
 				status := strings.ToUpper(s.Status[:1]) + s.Status[1:]
-				ip := s.Ip
+				ips := make([]string, 0, 2)
+				for _, ip := range []string{s.Ip, s.Ipv6} {
+					if ip != "" {
+						ips = append(ips, ip)
+					}
+				}
+
+				ip := strings.Join(ips, ",")
 				if ip == "" {
 					ip = "None"
 				}
@@ -17,6 +17,7 @@ import (
 
 	oapp "github.com/getoutreach/gobox/pkg/app"
 	gcli "github.com/getoutreach/gobox/pkg/cli"
+	"github.com/getoutreach/localizer/api"
 	"github.com/getoutreach/localizer/internal/kevents"
 	"github.com/getoutreach/localizer/internal/kube"
 	"github.com/getoutreach/localizer/internal/server"
@@ -82,6 +83,10 @@ func main() {
 			Usage: "Set the IP address CIDR, must include the /",
 			Value: "127.0.0.1/8",
 		},
+		&cli.StringFlag{
+			Name:  "ipv6-cidr",
+			Usage: "Set an IPv6 address CIDR to also allocate from, e.g. fd00:6c6f:6361:6c69::/64. Tunnels become dual-stack, or IPv6-only if --ip-cidr is empty",
+		},
 		&cli.StringFlag{
 			Name:  "namespace",
 			Usage: "Restrict forwarding to the given namespace. (default: all namespaces)",
@@ -90,12 +95,49 @@ func main() {
 			Name:  "skip-namespace",
 			Usage: "Skip forwarding services from the following namespace",
 		},
+		&cli.StringFlag{
+			Name:  "port-forward-transport",
+			Usage: "Protocol port-forwards are tunneled over, one of: websocket, spdy. websocket falls back to spdy when the API server doesn't support it",
+			Value: string(kube.PortForwardTransportWebSocket),
+		},
+		&cli.BoolFlag{
+			Name:  "vpn",
+			Usage: "Route the cluster's service and pod cidrs through a VPN to an agent in the cluster, instead of port-forwarding every service. Requires the agent to run privileged",
+		},
+		&cli.StringSliceFlag{
+			Name:  "vpn-cidr",
+			Usage: "Cidr to route through the VPN, defaults to the service and pod cidrs discovered from the cluster",
+		},
+		&cli.StringFlag{
+			Name:  "vpn-namespace",
+			Usage: "Namespace to create the VPN agent in",
+			Value: "default",
+		},
+		&cli.StringFlag{
+			Name:  "vpn-image",
+			Usage: "Image of the VPN agent, defaults to the ghcr.io/getoutreach/localizer-vpn image matching this version",
+		},
+		&cli.StringFlag{
+			Name:  "proxy",
+			Usage: "Run without root as a SOCKS5 and HTTP CONNECT proxy to the cluster listening on this address, e.g. 127.0.0.1:1080, instead of port-forwarding every service",
+		},
+		&cli.BoolFlag{
+			Name:  "proxy-allow-remote",
+			Usage: "Allow --proxy to listen on an address that isn't a loopback address. The proxy has no authentication, so anyone that can reach it can connect to the cluster",
+		},
+		&cli.StringFlag{
+			Name:  "expose-pod-config",
+			Usage: "YAML file configuring the image, pull secrets, resources, scheduling, service account and metadata of pods created by expose",
+		},
 		// <</Stencil::Block>>
 	}
 	app.Commands = []*cli.Command{
 		// <<Stencil::Block(commands)>>
 		NewListCommand(log),
 		NewExposeCommand(log),
+		NewEnvCommand(log),
+		NewRunCommand(log),
+		NewCaptureCommand(log),
 		// <</Stencil::Block>>
 	}
 
@@ -140,25 +182,60 @@ func main() {
 	}
 
 	app.Action = func(ctx context.Context, c *cli.Command) error {
-		u, err := user.Current()
-		if err != nil {
-			return errors.Wrap(err, "failed to get current user")
+		proxyAddress := c.String("proxy")
+		if proxyAddress != "" && c.Bool("vpn") {
+			return fmt.Errorf("--proxy and --vpn can't be used together")
 		}
 
-		if u.Uid != "0" {
-			return fmt.Errorf("must be run as root/Administrator")
+		// the proxy doesn't allocate addresses or edit the hosts file
+		if proxyAddress == "" {
+			u, err := user.Current()
+			if err != nil {
+				return errors.Wrap(err, "failed to get current user")
+			}
+
+			if u.Uid != "0" {
+				return fmt.Errorf("must be run as root/Administrator, or with --proxy")
+			}
 		}
 
 		clusterDomain := c.String("cluster-domain")
 		ipCidr := c.String("ip-cidr")
+		ipv6Cidr := c.String("ipv6-cidr")
 
 		log.Infof("using cluster domain: %v", clusterDomain)
-		log.Infof("using ip cidr: %v", ipCidr)
+		if ipCidr != "" && proxyAddress == "" {
+			log.Infof("using ip cidr: %v", ipCidr)
+		}
+		if ipv6Cidr != "" && proxyAddress == "" {
+			log.Infof("using ipv6 cidr: %v", ipv6Cidr)
+		}
+
+		var podOptions *api.ExposePodOptions
+		if c.String("expose-pod-config") != "" {
+			var err error
+			podOptions, err = loadPodOptions(c.String("expose-pod-config"))
+			if err != nil {
+				return err
+			}
+		}
 
 		srv := server.NewGRPCService(&server.RunOpts{
-			ClusterDomain: clusterDomain,
-			IPCidr:        ipCidr,
-			KubeContext:   c.String("context"),
+			ClusterDomain:    clusterDomain,
+			IPCidr:           ipCidr,
+			IPv6Cidr:         ipv6Cidr,
+			KubeContext:      c.String("context"),
+			ExposePodOptions: podOptions,
+
+			PortForwardTransport: c.String("port-forward-transport"),
+
+			VPN:          c.Bool("vpn"),
+			VPNCIDRs:     c.StringSlice("vpn-cidr"),
+			VPNNamespace: c.String("vpn-namespace"),
+			VPNImage:     c.String("vpn-image"),
+
+			ProxyAddress:     proxyAddress,
+			ProxyAllowRemote: c.Bool("proxy-allow-remote"),
 		})
 		return srv.Run(ctx, log)
 	}
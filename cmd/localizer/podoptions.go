@@ -0,0 +1,40 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package main.
+package main
+
+import (
+	"os"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"sigs.k8s.io/yaml"
+)
+
+// loadPodOptions reads expose pod options from a YAML, or JSON, file.
+// Field names match api.ExposePodOptions, for example:
+//
+//	image: registry.internal/linuxserver/openssh-server
+//	imagePullSecrets: [registry-creds]
+//	requests: {cpu: 50m, memory: 64Mi}
+//	nodeSelector: {kubernetes.io/os: linux}
+//	tolerations:
+//	  - {key: dedicated, operator: Equal, value: dev, effect: NoSchedule}
+func loadPodOptions(path string) (*api.ExposePodOptions, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read pod options")
+	}
+
+	jsonb, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse pod options %s", path)
+	}
+
+	var opts api.ExposePodOptions
+	if err := protojson.Unmarshal(jsonb, &opts); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse pod options %s", path)
+	}
+	return &opts, nil
+}
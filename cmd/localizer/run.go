@@ -0,0 +1,182 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package main.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/pkg/localizer"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func NewRunCommand(log logrus.FieldLogger) *cli.Command {
+	return &cli.Command{
+		Name:        "run",
+		Description: "Run a local process with Kubernetes style service environment variables injected",
+		Usage:       "run [--workload <namespace/service>] -- <command> [args...]",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "service",
+				Usage: "Only inject variables for the given namespace/service or namespace (default: the workload's namespace, or all services)",
+			},
+			&cli.StringFlag{
+				Name:  "workload",
+				Usage: "Also inject the env, ConfigMaps and Secrets of the controller powering the given namespace/service",
+			},
+			&cli.StringFlag{
+				Name:  "container",
+				Usage: "Container of the workload to read configuration from (default: the first container)",
+			},
+			&cli.DurationFlag{
+				Name:  "wait-timeout",
+				Usage: "How long to wait for the localizer daemon to become stable",
+				Value: 2 * time.Minute,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			args := c.Args().Slice()
+			if len(args) == 0 {
+				return fmt.Errorf("expected a command to run, i.e. localizer run -- ./bin/app")
+			}
+
+			var workloadNamespace, workloadService string
+			if c.String("workload") != "" {
+				split := strings.Split(c.String("workload"), "/")
+				if len(split) != 2 {
+					return fmt.Errorf("invalid workload, expected namespace/name")
+				}
+				workloadNamespace, workloadService = split[0], split[1]
+			}
+
+			if !localizer.IsRunning() {
+				return fmt.Errorf("localizer daemon not running (run localizer by itself?)")
+			}
+
+			env, err := getRunEnv(ctx, log, c, workloadNamespace, workloadService)
+			if err != nil {
+				return err
+			}
+
+			//nolint:gosec // Why: running the user's command is the point of this command
+			cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+			cmd.Stdin = os.Stdin
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			cmd.Env = append(os.Environ(), env...)
+
+			// Give the process a chance to shutdown gracefully when we're
+			// interrupted instead of killing it outright.
+			cmd.Cancel = func() error { return cmd.Process.Signal(syscall.SIGTERM) }
+			cmd.WaitDelay = 10 * time.Second
+
+			if err := cmd.Run(); err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					return cli.Exit("", exitErr.ExitCode())
+				}
+				return errors.Wrap(err, "failed to run command")
+			}
+
+			return nil
+		},
+	}
+}
+
+// getRunEnv waits for the localizer daemon to be stable and returns the
+// environment, in key=value format, that should be injected into the
+// process being ran.
+func getRunEnv(ctx context.Context, log logrus.FieldLogger, c *cli.Command,
+	workloadNamespace, workloadService string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("wait-timeout")+30*time.Second)
+	defer cancel()
+
+	// nolint: staticcheck // Why: we are not upgrading to the new grpc API yet.
+	client, closer, err := localizer.Connect(ctx, grpc.WithBlock(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to localizer daemon")
+	}
+	defer closer()
+
+	if err := waitForStable(ctx, log, client, c.Duration("wait-timeout")); err != nil {
+		return nil, err
+	}
+
+	services := c.StringSlice("service")
+	if len(services) == 0 && workloadNamespace != "" {
+		services = []string{workloadNamespace}
+	}
+
+	resp, err := client.Env(ctx, &api.EnvRequest{Services: services})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get service environment")
+	}
+
+	env := make([]string, 0, len(resp.Variables))
+	for k, v := range resp.Variables {
+		env = append(env, k+"="+v)
+	}
+
+	if workloadNamespace == "" {
+		return env, nil
+	}
+
+	workload, err := client.WorkloadConfig(ctx, &api.WorkloadConfigRequest{
+		Namespace: workloadNamespace,
+		Service:   workloadService,
+		Container: c.String("container"),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get workload configuration")
+	}
+	log.Infof("using configuration from container %q of %s", workload.Container, workload.Controller)
+
+	// The workload's environment is appended last so that it takes
+	// precedence, this matches how the kubelet handles explicitly set
+	// variables.
+	for k, v := range workload.Env {
+		env = append(env, k+"="+v)
+	}
+
+	return env, nil
+}
+
+// waitForStable waits for the localizer daemon to report that it's
+// stable, meaning that the initial set of tunnels has been created.
+func waitForStable(ctx context.Context, log logrus.FieldLogger, client api.LocalizerServiceClient, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	logged := false
+	for {
+		resp, err := client.Stable(ctx, &api.Empty{})
+		if err == nil && resp.Stable {
+			return nil
+		}
+
+		if !logged {
+			log.Info("waiting for localizer daemon to become stable")
+			logged = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "localizer daemon did not become stable")
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package agent.
+
+// Package agent implements the reverse tunnel spoken between localizer
+// and the localizer-agent running in expose pods. Connections are
+// multiplexed with yamux over a mutually authenticated TLS connection,
+// which itself is carried over a Kubernetes port-forward.
+package agent
@@ -0,0 +1,190 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package agent.
+package agent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/ssh"
+	"github.com/getoutreach/localizer/internal/tun"
+	"github.com/sirupsen/logrus"
+)
+
+// freePort returns a port that is currently free on localhost
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port //nolint:errcheck // Why: test
+}
+
+// startServer starts an agent using creds' host key that authorizes
+// authorized's client key, returning the port it's listening on. The
+// VPN is enabled if dev isn't nil.
+func startServer(ctx context.Context, t *testing.T, creds, authorized *ssh.Credentials, dev tun.Device) int {
+	srv, err := NewServer(logrus.New(), creds.HostKey(), authorized.ClientKey().Public().(ed25519.PublicKey)) //nolint:errcheck // Why: test
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev != nil {
+		srv.EnableVPN(dev)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(ctx, l) //nolint:errcheck // Why: test
+
+	return l.Addr().(*net.TCPAddr).Port //nolint:errcheck // Why: test
+}
+
+func TestTunnel(t *testing.T) {
+	t.Run("tcp", func(t *testing.T) {
+		local, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		testTunnel(t, local, strconv.Itoa(local.Addr().(*net.TCPAddr).Port)) //nolint:errcheck // Why: test
+	})
+
+	t.Run("unix", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.sock")
+		local, err := net.Listen("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testTunnel(t, local, "unix://"+path)
+	})
+}
+
+// testTunnel tunnels a remote port to target, which local is listening on
+func testTunnel(t *testing.T, local net.Listener, target string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	creds, err := ssh.GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// local service that echoes back what it receives
+	defer local.Close()
+	go func() {
+		for {
+			conn, err := local.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn) //nolint:errcheck // Why: test
+			}()
+		}
+	}()
+
+	agentPort := startServer(ctx, t, creds, creds, nil)
+	remotePort := freePort(t)
+
+	ports := []string{fmt.Sprintf("%s:%d", target, remotePort)}
+	client, err := NewReverseTunnelClient(logrus.New(), "127.0.0.1", agentPort, ports, creds, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() { errChan <- client.Start(ctx, "default/test") }()
+
+	// wait for the agent to listen on the remote port
+	var conn net.Conn
+	for range 50 {
+		conn, err = net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", remotePort))
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("agent never listened on remote port: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "hello" {
+		t.Errorf("expected echo of hello, got %q", buf)
+	}
+
+	cancel()
+	if err := <-errChan; err != nil {
+		t.Errorf("expected Start to return nil when canceled, got %v", err)
+	}
+}
+
+func TestTunnel_RejectsUnknownKeys(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	creds, err := ssh.GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ssh.GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// agent with a different host key, and authorized client key
+	agentPort := startServer(ctx, t, other, other, nil)
+
+	client, err := NewReverseTunnelClient(logrus.New(), "127.0.0.1", agentPort, []string{"8080"}, creds, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Start(ctx, "default/test"); err == nil {
+		t.Error("expected connecting to an agent with an unknown host key to fail")
+	}
+}
+
+func TestServer_ReplacesSession(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	creds, err := ssh.GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentPort := startServer(ctx, t, creds, creds, nil)
+	req := &listenRequest{Ports: []uint16{uint16(freePort(t))}} //nolint:gosec // Why: test
+
+	// every session listens on the same port as the one it replaces,
+	// which only works once the previous listeners were closed
+	for range 20 {
+		session, err := dialSession(ctx, logrus.New(), "127.0.0.1", agentPort, creds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer session.Close()
+
+		if _, err := openControl(session, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the tunnel client ran by localizer.
+package agent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/function61/gokit/io/bidipipe"
+	"github.com/getoutreach/localizer/internal/capture"
+	"github.com/getoutreach/localizer/internal/ssh"
+	"github.com/hashicorp/yamux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrSessionClosed is returned by Client.Start when the session with the
+// agent was closed, usually because the port-forward died.
+var ErrSessionClosed = errors.New("tunnel session closed")
+
+// Client is a reverse tunnel client for the localizer agent. It's a
+// drop-in replacement for ssh.Client.
+type Client struct {
+	log logrus.FieldLogger
+
+	// host of the remote agent
+	host string
+
+	// port of the remote agent
+	port int
+
+	// ports is the ports this client hosts, with the format being
+	// remotePort target
+	ports map[uint16]ssh.Target
+
+	// creds are used to authenticate with, and verify, the agent
+	creds *ssh.Credentials
+
+	// ready is closed once the agent is listening on every port
+	ready chan struct{}
+
+	// active is the number of connections currently being tunneled
+	active atomic.Int64
+
+	// capture records tunneled connections
+	capture *capture.Registry
+}
+
+// NewReverseTunnelClient creates a new agent powered reverse tunnel
+// client. Ports are in the format accepted by ssh.ParsePortMap,
+// connections are recorded to registry if it's not nil.
+func NewReverseTunnelClient(log logrus.FieldLogger, host string, port int, ports []string,
+	creds *ssh.Credentials, registry *capture.Registry) (*Client, error) {
+	portMap := make(map[uint16]ssh.Target, len(ports))
+	for _, p := range ports {
+		remotePort, target, err := ssh.ParsePortMap(p)
+		if err != nil {
+			return nil, err
+		}
+		portMap[remotePort] = target
+	}
+
+	return &Client{
+		log:     log,
+		host:    host,
+		port:    port,
+		ports:   portMap,
+		creds:   creds,
+		ready:   make(chan struct{}),
+		capture: registry,
+	}, nil
+}
+
+// Start starts the tunnel. This blocks until the session is closed or
+// the context is canceled.
+func (c *Client) Start(ctx context.Context, serviceKey string) error {
+	session, err := dialSession(ctx, c.log, c.host, c.port, c.creds)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	req := listenRequest{Ports: make([]uint16, 0, len(c.ports))}
+	for remotePort := range c.ports {
+		req.Ports = append(req.Ports, remotePort)
+	}
+	control, err := openControl(session, &req)
+	if err != nil {
+		return err
+	}
+	defer control.Close()
+
+	for remotePort, target := range c.ports {
+		c.log.Infof("created tunnel from remote %s:%d to %s", serviceKey, remotePort, target)
+	}
+	close(c.ready)
+
+	go func() {
+		<-ctx.Done()
+		session.Close()
+	}()
+
+	for {
+		stream, err := session.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return ErrSessionClosed
+		}
+
+		go c.handleStream(ctx, serviceKey, stream)
+	}
+}
+
+// dialSession connects to the agent at host:port and creates a session
+// over the connection, which is closed along with the session
+func dialSession(ctx context.Context, log logrus.FieldLogger, host string, port int,
+	creds *ssh.Credentials) (*yamux.Session, error) {
+	hostKey, ok := creds.HostKey().Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("expected host key to be an ed25519 key")
+	}
+
+	conf, err := clientTLSConfig(creds.ClientKey(), hostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 10 * time.Second},
+		Config:    conf,
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to agent")
+	}
+
+	session, err := yamux.Client(conn, yamuxConfig(log))
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to create session")
+	}
+	return session, nil
+}
+
+// openControl opens the control stream of a session and sends req over
+// it, returning the stream once the agent accepted it
+func openControl(session *yamux.Session, req *listenRequest) (net.Conn, error) {
+	control, err := session.Open()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open control stream")
+	}
+
+	if err := json.NewEncoder(control).Encode(req); err != nil {
+		control.Close()
+		return nil, errors.Wrap(err, "failed to send listen request")
+	}
+
+	var resp listenResponse
+	dec := json.NewDecoder(control)
+	if err := dec.Decode(&resp); err != nil {
+		control.Close()
+		return nil, errors.Wrap(err, "failed to read listen response")
+	}
+	if resp.Error != "" {
+		control.Close()
+		return nil, fmt.Errorf("agent failed to listen: %s", resp.Error)
+	}
+
+	conn, err := withBuffered(control, dec)
+	if err != nil {
+		control.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Ready returns a channel that's closed once the agent is listening on
+// every port, i.e. once traffic is being tunneled
+func (c *Client) Ready() <-chan struct{} {
+	return c.ready
+}
+
+// Active returns the number of connections currently being tunneled
+func (c *Client) Active() int {
+	return int(c.active.Load())
+}
+
+// handleStream proxies a stream opened by the agent to the target its
+// port is mapped to
+func (c *Client) handleStream(ctx context.Context, serviceKey string, stream net.Conn) {
+	defer stream.Close()
+
+	c.active.Add(1)
+	defer c.active.Add(-1)
+
+	remotePort, err := readStreamHeader(stream)
+	if err != nil {
+		c.log.WithError(err).Warn("failed to read stream header")
+		return
+	}
+
+	target, ok := c.ports[remotePort]
+	if !ok {
+		c.log.Warnf("agent opened a stream for unknown port %d", remotePort)
+		return
+	}
+
+	local, err := target.Dial(ctx)
+	if err != nil {
+		c.log.WithError(err).Errorf("failed to dial local service (is anything listening at %q?)", target)
+		return
+	}
+	stream = c.capture.Tap(serviceKey, remotePort, stream, stream.RemoteAddr().String(), target.Address)
+
+	if err := bidipipe.Pipe(bidipipe.WithName("tunnel", stream), bidipipe.WithName("local", local)); err != nil {
+		c.log.WithError(err).Warnf("failed to send data over tunnel")
+	}
+}
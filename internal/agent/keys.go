@@ -0,0 +1,63 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains helpers for loading the agent's keys.
+package agent
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// HostKeyFile is the name of the file containing the agent's private
+	// key, in the OpenSSH format
+	HostKeyFile = "ssh_host_ed25519_key"
+
+	// AuthorizedKeysFile is the name of the file containing the public key
+	// of the client, in the authorized_keys format
+	AuthorizedKeysFile = "authorized_keys"
+)
+
+// LoadKeys loads the host key and authorized client key from dir. These
+// use the same format as OpenSSH so that the credentials secret created
+// by expose works with every tunnel image.
+func LoadKeys(dir string) (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	b, err := os.ReadFile(filepath.Join(dir, HostKeyFile))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read host key")
+	}
+
+	rawKey, err := ssh.ParseRawPrivateKey(b)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse host key")
+	}
+	hostKey, ok := rawKey.(*ed25519.PrivateKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected host key to be an ed25519 key, got %T", rawKey)
+	}
+
+	b, err = os.ReadFile(filepath.Join(dir, AuthorizedKeysFile))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read authorized key")
+	}
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(b)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse authorized key")
+	}
+	cryptoPub, ok := pub.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported authorized key type %s", pub.Type())
+	}
+	authorized, ok := cryptoPub.CryptoPublicKey().(ed25519.PublicKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected authorized key to be an ed25519 key, got %s", pub.Type())
+	}
+
+	return *hostKey, authorized, nil
+}
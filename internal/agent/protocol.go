@@ -0,0 +1,111 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the messages exchanged over a tunnel.
+package agent
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/hashicorp/yamux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// listenRequest is sent by the client on the first stream of a session
+// to ask the agent to listen on ports, this is the equivalent of a SSH
+// tcpip-forward request. The stream stays open for the lifetime of the
+// session.
+type listenRequest struct {
+	Ports []uint16 `json:"ports"`
+
+	// VPN asks the agent to relay packets between its TUN device and the
+	// control stream instead of listening on ports, see Server.EnableVPN
+	VPN bool `json:"vpn,omitempty"`
+}
+
+// listenResponse is the agent's response to a listenRequest
+type listenResponse struct {
+	// Error is set if the agent failed to listen on any of the ports
+	Error string `json:"error,omitempty"`
+}
+
+// bufferedConn is a net.Conn that reads from r instead of the conn
+type bufferedConn struct {
+	net.Conn
+
+	r io.Reader
+}
+
+// Read implements io.Reader
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// withBuffered returns a conn that first returns the data read from conn
+// but not yet consumed by dec, which is needed when the control stream is
+// used for more than its JSON messages. The newline written by
+// json.Encoder after the last message is skipped.
+func withBuffered(conn net.Conn, dec *json.Decoder) (net.Conn, error) {
+	r := io.MultiReader(dec.Buffered(), conn)
+
+	var newline [1]byte
+	if _, err := io.ReadFull(r, newline[:]); err != nil {
+		return nil, errors.Wrap(err, "failed to read end of message")
+	}
+	if newline[0] != '\n' {
+		return nil, fmt.Errorf("expected newline after message, got %q", newline[0])
+	}
+
+	return &bufferedConn{Conn: conn, r: r}, nil
+}
+
+// writeStreamHeader writes the header of a stream opened by the agent
+// for an accepted connection, which is the port it was accepted on.
+func writeStreamHeader(w io.Writer, port uint16) error {
+	return binary.Write(w, binary.BigEndian, port)
+}
+
+// readStreamHeader reads the header written by writeStreamHeader
+func readStreamHeader(r io.Reader) (uint16, error) {
+	var port uint16
+	err := binary.Read(r, binary.BigEndian, &port)
+	return port, err
+}
+
+// yamuxConfig returns the yamux configuration for both sides of a
+// tunnel. Keep-alives are used to detect when the underlying
+// port-forward has died.
+func yamuxConfig(log logrus.FieldLogger) *yamux.Config {
+	conf := yamux.DefaultConfig()
+	conf.EnableKeepAlive = true
+	conf.KeepAliveInterval = 5 * time.Second
+	conf.ConnectionWriteTimeout = 10 * time.Second
+	conf.LogOutput = nil
+	conf.Logger = yamuxLogger{log}
+	return conf
+}
+
+// yamuxLogger adapts a logrus.FieldLogger to yamux.Logger
+type yamuxLogger struct {
+	log logrus.FieldLogger
+}
+
+// Print implements yamux.Logger
+func (l yamuxLogger) Print(v ...interface{}) {
+	l.log.Debug(v...)
+}
+
+// Printf implements yamux.Logger
+func (l yamuxLogger) Printf(format string, v ...interface{}) {
+	l.log.Debugf(format, v...)
+}
+
+// Println implements yamux.Logger
+func (l yamuxLogger) Println(v ...interface{}) {
+	l.log.Debug(v...)
+}
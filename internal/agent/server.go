@@ -0,0 +1,251 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the tunnel server ran by the agent in expose pods.
+package agent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/function61/gokit/io/bidipipe"
+	"github.com/getoutreach/localizer/internal/tun"
+	"github.com/hashicorp/yamux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Server accepts tunnel sessions from localizer and, for each session,
+// listens on the requested ports and streams accepted connections back
+// to localizer.
+type Server struct {
+	log logrus.FieldLogger
+	tls *tls.Config
+
+	// mu ensures only one session is active at a time, a new session
+	// replaces the previous one since it's usually the result of the
+	// port-forward being recreated.
+	mu      sync.Mutex
+	current *serverSession
+
+	// vpn is the device packets are relayed to, if the VPN is enabled
+	vpn tun.Device
+}
+
+// serverSession is a session being served by the agent
+type serverSession struct {
+	*yamux.Session
+
+	// done is closed once the session stopped being served, at which
+	// point its listeners are closed
+	done chan struct{}
+}
+
+// NewServer creates a new tunnel server using the provided host key and
+// only accepting clients that authenticate with the authorized key.
+func NewServer(log logrus.FieldLogger, hostKey ed25519.PrivateKey, authorized ed25519.PublicKey) (*Server, error) {
+	conf, err := serverTLSConfig(hostKey, authorized)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{log: log, tls: conf}, nil
+}
+
+// EnableVPN allows sessions to relay packets to dev, instead of listening
+// on ports. This must be called before Serve.
+func (s *Server) EnableVPN(dev tun.Device) {
+	s.vpn = dev
+}
+
+// Serve accepts tunnel sessions on l until the context is canceled
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "failed to accept connection")
+		}
+
+		go s.handleConn(ctx, conn)
+	}
+}
+
+// handleConn authenticates a connection and serves a session over it
+func (s *Server) handleConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	log := s.log.WithField("remote", conn.RemoteAddr().String())
+
+	tlsConn := tls.Server(conn, s.tls)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		// readiness probes connect without speaking TLS, so this is
+		// expected to happen regularly
+		log.WithError(err).Debug("tls handshake failed")
+		return
+	}
+
+	session, err := yamux.Server(tlsConn, yamuxConfig(log))
+	if err != nil {
+		log.WithError(err).Warn("failed to create session")
+		return
+	}
+	defer session.Close()
+
+	// the new session usually listens on the same ports, so wait for the
+	// previous one to close its listeners before replacing it
+	current := &serverSession{Session: session, done: make(chan struct{})}
+	defer close(current.done)
+
+	s.mu.Lock()
+	if s.current != nil {
+		log.Info("replacing existing session")
+		s.current.Close()
+		<-s.current.done
+	}
+	s.current = current
+	s.mu.Unlock()
+
+	log.Info("session established")
+	if err := s.serveSession(ctx, log, session); err != nil {
+		log.WithError(err).Warn("session failed")
+	}
+	log.Info("session closed")
+}
+
+// serveSession handles the listen request of a session and streams
+// connections back until the session is closed.
+func (s *Server) serveSession(ctx context.Context, log logrus.FieldLogger, session *yamux.Session) error {
+	control, err := session.Accept()
+	if err != nil {
+		return errors.Wrap(err, "failed to accept control stream")
+	}
+	defer control.Close()
+
+	var req listenRequest
+	dec := json.NewDecoder(control)
+	if err := dec.Decode(&req); err != nil {
+		return errors.Wrap(err, "failed to read listen request")
+	}
+
+	if req.VPN {
+		//nolint:govet // Why: We're OK shadowing err
+		conn, err := withBuffered(control, dec)
+		if err != nil {
+			return err
+		}
+		return s.serveVPN(ctx, log, session, conn)
+	}
+
+	listeners := make([]net.Listener, 0, len(req.Ports))
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+
+	var listenErr error
+	for _, port := range req.Ports {
+		l, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(int(port))))
+		if err != nil {
+			listenErr = errors.Wrapf(err, "failed to listen on port %d", port)
+			break
+		}
+		listeners = append(listeners, l)
+	}
+
+	resp := listenResponse{}
+	if listenErr != nil {
+		resp.Error = listenErr.Error()
+	}
+	if err := json.NewEncoder(control).Encode(resp); err != nil {
+		return errors.Wrap(err, "failed to write listen response")
+	}
+	if listenErr != nil {
+		return listenErr
+	}
+
+	for i, l := range listeners {
+		go s.acceptConns(log, session, l, req.Ports[i])
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-session.CloseChan():
+	}
+	return nil
+}
+
+// serveVPN relays packets between the VPN's device and the control
+// stream until the session is closed
+func (s *Server) serveVPN(ctx context.Context, log logrus.FieldLogger, session *yamux.Session, control net.Conn) error {
+	resp := listenResponse{}
+	if s.vpn == nil {
+		resp.Error = "vpn is not enabled"
+	}
+	if err := json.NewEncoder(control).Encode(resp); err != nil {
+		return errors.Wrap(err, "failed to write listen response")
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+
+	log.Info("relaying vpn packets")
+	errC := make(chan error, 1)
+	go func() { errC <- tun.Relay(s.vpn, tun.NewStreamDevice(control)) }()
+
+	select {
+	case <-ctx.Done():
+	case <-session.CloseChan():
+	case err := <-errC:
+		if !session.IsClosed() {
+			return errors.Wrap(err, "failed to relay packets")
+		}
+	}
+	return nil
+}
+
+// acceptConns accepts connections on l and streams them to the client
+// until l is closed.
+func (s *Server) acceptConns(log logrus.FieldLogger, session *yamux.Session, l net.Listener, port uint16) {
+	log = log.WithField("port", port)
+	log.Info("listening")
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			stream, err := session.Open()
+			if err != nil {
+				log.WithError(err).Warn("failed to open stream")
+				return
+			}
+			defer stream.Close()
+
+			if err := writeStreamHeader(stream, port); err != nil {
+				log.WithError(err).Warn("failed to write stream header")
+				return
+			}
+
+			err = bidipipe.Pipe(bidipipe.WithName("client", conn), bidipipe.WithName("tunnel", stream))
+			if err != nil {
+				log.WithError(err).Debugf("connection from %s closed", conn.RemoteAddr())
+			}
+		}()
+	}
+}
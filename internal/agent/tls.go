@@ -0,0 +1,90 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the TLS configuration used to authenticate tunnels.
+package agent
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// certificate creates a self-signed certificate for an ed25519 key. The
+// certificate itself isn't trusted, peers are authenticated by their
+// public key instead.
+func certificate(key ed25519.PrivateKey) (tls.Certificate, error) {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * 365 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrap(err, "failed to create certificate")
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// verifyPeerKey returns a function, for tls.Config.VerifyPeerCertificate,
+// that only accepts a peer presenting a certificate for the given key.
+func verifyPeerKey(expected ed25519.PublicKey) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("peer presented no certificate")
+		}
+
+		cert, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to parse peer certificate")
+		}
+
+		key, ok := cert.PublicKey.(ed25519.PublicKey)
+		if !ok || !key.Equal(expected) {
+			return fmt.Errorf("peer presented an unknown key")
+		}
+		return nil
+	}
+}
+
+// serverTLSConfig returns the TLS configuration for the agent, which
+// only accepts clients authenticating with the authorized key.
+func serverTLSConfig(hostKey ed25519.PrivateKey, authorized ed25519.PublicKey) (*tls.Config, error) {
+	cert, err := certificate(hostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:            tls.VersionTLS13,
+		Certificates:          []tls.Certificate{cert},
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: verifyPeerKey(authorized),
+	}, nil
+}
+
+// clientTLSConfig returns the TLS configuration for localizer, which
+// only accepts an agent presenting the host key.
+func clientTLSConfig(clientKey ed25519.PrivateKey, hostKey ed25519.PublicKey) (*tls.Config, error) {
+	cert, err := certificate(clientKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS13,
+		Certificates: []tls.Certificate{cert},
+		// The certificate chain isn't verified, the peer's key is instead.
+		InsecureSkipVerify:    true, //nolint:gosec // Why: verified by VerifyPeerCertificate
+		VerifyPeerCertificate: verifyPeerKey(hostKey),
+	}, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the VPN client ran by localizer.
+package agent
+
+import (
+	"context"
+
+	"github.com/getoutreach/localizer/internal/ssh"
+	"github.com/getoutreach/localizer/internal/tun"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// VPNClient relays packets between a local device and the device of an
+// agent that has the VPN enabled, see Server.EnableVPN
+type VPNClient struct {
+	log logrus.FieldLogger
+
+	// host and port of the remote agent
+	host string
+	port int
+
+	// creds are used to authenticate with, and verify, the agent
+	creds *ssh.Credentials
+}
+
+// NewVPNClient creates a new VPN client for the agent at host:port
+func NewVPNClient(log logrus.FieldLogger, host string, port int, creds *ssh.Credentials) *VPNClient {
+	return &VPNClient{log: log, host: host, port: port, creds: creds}
+}
+
+// Start relays packets between dev and the agent. This blocks until the
+// session is closed, returning ErrSessionClosed, or the context is
+// canceled. dev isn't closed, so it can be reused by another session.
+func (c *VPNClient) Start(ctx context.Context, dev tun.Device) error {
+	session, err := dialSession(ctx, c.log, c.host, c.port, c.creds)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	control, err := openControl(session, &listenRequest{VPN: true})
+	if err != nil {
+		return err
+	}
+	defer control.Close()
+
+	c.log.Info("relaying vpn packets")
+	errC := make(chan error, 1)
+	go func() { errC <- tun.Relay(dev, tun.NewStreamDevice(control)) }()
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-session.CloseChan():
+		return ErrSessionClosed
+	case err := <-errC:
+		if session.IsClosed() {
+			return ErrSessionClosed
+		}
+		return errors.Wrap(err, "failed to relay packets")
+	}
+}
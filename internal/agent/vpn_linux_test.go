@@ -0,0 +1,100 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the network namespace tests of the VPN.
+
+//go:build linux
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/ssh"
+	"github.com/getoutreach/localizer/internal/tun"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// TestVPN_TUN routes a cidr through a real TUN device, inside of a new
+// network namespace, to a fake agent
+func TestVPN_TUN(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating a network namespace requires root")
+	}
+	if _, err := exec.LookPath("ip"); err != nil {
+		t.Skip("ip is required to configure the tun device")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	creds, err := ssh.GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentPort := startServer(ctx, t, creds, creds, newEchoDevice())
+
+	// Only the goroutine's thread enters the namespace, it's never
+	// unlocked so that it's discarded afterwards. The session runs on
+	// other threads, so it connects to the agent in this namespace.
+	errChan := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		errChan <- testTUN(ctx, agentPort, creds)
+	}()
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testTUN creates a TUN device in a new network namespace, connected
+// to the agent listening on agentPort, and sends a UDP packet over it
+func testTUN(ctx context.Context, agentPort int, creds *ssh.Credentials) error {
+	if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+		return err
+	}
+
+	dev, err := tun.Open("vpntest%d")
+	if err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	if err := dev.Configure(tun.ClientAddress, tun.AgentAddress, []netip.Prefix{netip.MustParsePrefix("10.96.0.0/12")}); err != nil {
+		return err
+	}
+	go NewVPNClient(logrus.New(), "127.0.0.1", agentPort, creds).Start(ctx, dev) //nolint:errcheck // Why: test
+
+	conn, err := net.Dial("udp", "10.96.0.10:53")
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// retry until the session has been established
+	buf := make([]byte, 16)
+	for range 50 {
+		if _, err := conn.Write([]byte("hello")); err != nil {
+			return err
+		}
+
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond)) //nolint:errcheck // Why: test
+		n, err := conn.Read(buf)
+		if err != nil {
+			continue
+		}
+		if string(buf[:n]) != "hello" {
+			return fmt.Errorf("expected echo of hello, got %q", buf[:n])
+		}
+		return nil
+	}
+	return fmt.Errorf("never received a reply through the tun device")
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package agent.
+package agent
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/ssh"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+// ipProtoUDP is the IPv4 protocol number of UDP
+const ipProtoUDP = 17
+
+// chanDevice is an in-memory tun.Device, packets sent to in are read
+// from it and packets written to it are sent to out
+type chanDevice struct {
+	in  chan []byte
+	out chan []byte
+
+	done chan struct{}
+	once sync.Once
+}
+
+func newChanDevice() *chanDevice {
+	return &chanDevice{in: make(chan []byte, 16), out: make(chan []byte, 16), done: make(chan struct{})}
+}
+
+func (d *chanDevice) Read(b []byte) (int, error) {
+	select {
+	case p := <-d.in:
+		return copy(b, p), nil
+	case <-d.done:
+		return 0, io.EOF
+	}
+}
+
+func (d *chanDevice) Write(b []byte) (int, error) {
+	select {
+	case d.out <- slices.Clone(b):
+		return len(b), nil
+	case <-d.done:
+		return 0, io.ErrClosedPipe
+	}
+}
+
+func (d *chanDevice) Close() error {
+	d.once.Do(func() { close(d.done) })
+	return nil
+}
+
+// newEchoDevice returns the device of a fake agent, which replies to
+// every UDP packet sent to it by swapping its addresses and ports. The
+// checksums of the packet stay valid, since they're sums.
+func newEchoDevice() *chanDevice {
+	d := newChanDevice()
+	go func() {
+		for {
+			select {
+			case p := <-d.out:
+				ihl := int(p[0]&0x0f) * 4
+				if p[0]>>4 != 4 || p[9] != ipProtoUDP || len(p) < ihl+8 {
+					continue
+				}
+
+				reply := slices.Clone(p)
+				copy(reply[12:16], p[16:20])
+				copy(reply[16:20], p[12:16])
+				copy(reply[ihl:ihl+2], p[ihl+2:ihl+4])
+				copy(reply[ihl+2:ihl+4], p[ihl:ihl+2])
+				d.in <- reply
+			case <-d.done:
+				return
+			}
+		}
+	}()
+	return d
+}
+
+// udpPacket returns an IPv4 UDP packet from src to dst, its checksums
+// aren't set
+func udpPacket(src, dst netip.AddrPort, payload string) []byte {
+	p := make([]byte, 28+len(payload))
+	p[0] = 0x45
+	binary.BigEndian.PutUint16(p[2:], uint16(len(p))) //nolint:gosec // Why: test
+	p[8] = 64
+	p[9] = ipProtoUDP
+	copy(p[12:16], src.Addr().AsSlice())
+	copy(p[16:20], dst.Addr().AsSlice())
+	binary.BigEndian.PutUint16(p[20:], src.Port())
+	binary.BigEndian.PutUint16(p[22:], dst.Port())
+	binary.BigEndian.PutUint16(p[24:], uint16(8+len(payload))) //nolint:gosec // Why: test
+	copy(p[28:], payload)
+	return p
+}
+
+func TestVPN(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	creds, err := ssh.GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentPort := startServer(ctx, t, creds, creds, newEchoDevice())
+
+	local := newChanDevice()
+	errChan := make(chan error, 1)
+	go func() { errChan <- NewVPNClient(logrus.New(), "127.0.0.1", agentPort, creds).Start(ctx, local) }()
+
+	client := netip.MustParseAddrPort("198.18.0.2:40000")
+	service := netip.MustParseAddrPort("10.96.0.10:53")
+	for _, payload := range []string{"hello", "world"} {
+		local.in <- udpPacket(client, service, payload)
+
+		select {
+		case got := <-local.out:
+			if diff := cmp.Diff(udpPacket(service, client, payload), got); diff != "" {
+				t.Errorf("unexpected reply (-want +got):\n%s", diff)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for reply")
+		}
+	}
+
+	cancel()
+	if err := <-errChan; err != nil {
+		t.Errorf("expected Start to return nil when canceled, got %v", err)
+	}
+}
+
+func TestVPN_NotEnabled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	creds, err := ssh.GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentPort := startServer(ctx, t, creds, creds, nil)
+
+	err = NewVPNClient(logrus.New(), "127.0.0.1", agentPort, creds).Start(ctx, newChanDevice())
+	if err == nil || !strings.Contains(err.Error(), "vpn is not enabled") {
+		t.Errorf("expected vpn is not enabled error, got %v", err)
+	}
+}
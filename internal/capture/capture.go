@@ -0,0 +1,241 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the registry of captures that tunnels record connections to.
+package capture
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// eventBuffer is the number of events buffered for a capture, events are
+// dropped when it's full rather than slowing down the tunnel
+const eventBuffer = 4096
+
+// EventType is the type of an Event
+type EventType string
+
+// This block contains the types of events
+const (
+	// EventOpen is recorded when a connection is opened
+	EventOpen EventType = "open"
+
+	// EventData is recorded for every chunk of data sent over a
+	// connection
+	EventData EventType = "data"
+
+	// EventClose is recorded when a connection is closed
+	EventClose EventType = "close"
+)
+
+// Direction is the side of a connection that sent data
+type Direction string
+
+// This block contains the directions data is sent in
+const (
+	// DirectionClient is data sent by the side that opened the
+	// connection, e.g. a local process connecting to a tunnel
+	DirectionClient Direction = "client"
+
+	// DirectionServer is data sent by the side that accepted the
+	// connection, e.g. the pod a tunnel connects to
+	DirectionServer Direction = "server"
+)
+
+// Event is something that happened on a captured connection
+type Event struct {
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+
+	// Conn identifies the connection, it's unique within a capture
+	Conn uint64 `json:"conn"`
+
+	// Client and Server are the addresses of both sides of the
+	// connection, they're only set on EventOpen
+	Client string `json:"client,omitempty"`
+	Server string `json:"server,omitempty"`
+
+	// Direction is the side that sent Data, only set on EventData
+	Direction Direction `json:"direction,omitempty"`
+	Data      []byte    `json:"data,omitempty"`
+}
+
+// Capture records the connections of a service's port
+type Capture struct {
+	// Service is the service being captured, in the format namespace/name
+	Service string
+
+	// Port is the port being captured, or 0 for every port
+	Port uint16
+
+	// maxBytes is the amount of data to record before stopping, 0 means
+	// there's no limit
+	maxBytes int64
+	recorded atomic.Int64
+	dropped  atomic.Int64
+
+	events   chan Event
+	done     chan struct{}
+	stopOnce sync.Once
+	conns    atomic.Uint64
+}
+
+// Events returns the recorded events
+func (c *Capture) Events() <-chan Event {
+	return c.events
+}
+
+// Done returns a channel that's closed once the capture has stopped,
+// either because it was stopped or it reached its size limit
+func (c *Capture) Done() <-chan struct{} {
+	return c.done
+}
+
+// Dropped returns the number of events that were dropped because they
+// weren't read fast enough
+func (c *Capture) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+// stop stops recording events
+func (c *Capture) stop() {
+	c.stopOnce.Do(func() { close(c.done) })
+}
+
+// record sends an event, unless the capture was stopped or isn't being
+// read from fast enough
+func (c *Capture) record(e *Event) {
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+
+	if e.Type == EventData && c.maxBytes != 0 {
+		if c.recorded.Add(int64(len(e.Data))) > c.maxBytes {
+			c.stop()
+			return
+		}
+	}
+
+	select {
+	case c.events <- *e:
+	default:
+		c.dropped.Add(1)
+	}
+}
+
+// Registry keeps track of the running captures, tunnels look up the
+// captures for their connections through Tap. A nil Registry captures
+// nothing.
+type Registry struct {
+	mu       sync.Mutex
+	captures map[*Capture]struct{}
+}
+
+// NewRegistry creates a new Registry
+func NewRegistry() *Registry {
+	return &Registry{captures: make(map[*Capture]struct{})}
+}
+
+// Start starts capturing the connections to a port of a service, or
+// every port if port is 0. Recording stops after maxBytes of data, unless
+// it's 0.
+func (r *Registry) Start(service string, port uint16, maxBytes int64) *Capture {
+	c := &Capture{
+		Service:  service,
+		Port:     port,
+		maxBytes: maxBytes,
+		events:   make(chan Event, eventBuffer),
+		done:     make(chan struct{}),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.captures[c] = struct{}{}
+	return c
+}
+
+// Stop stops a capture
+func (r *Registry) Stop(c *Capture) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.captures, c)
+	c.stop()
+}
+
+// Tap returns conn, recording what's sent over it to the captures of the
+// service's port. conn is the client side of the connection: what's read
+// from it was sent by the client, and what's written to it by the server.
+func (r *Registry) Tap(service string, port uint16, conn net.Conn, client, server string) net.Conn {
+	if r == nil {
+		return conn
+	}
+
+	r.mu.Lock()
+	var captures []*Capture
+	for c := range r.captures {
+		if c.Service == service && (c.Port == 0 || c.Port == port) {
+			captures = append(captures, c)
+		}
+	}
+	r.mu.Unlock()
+
+	if len(captures) == 0 {
+		return conn
+	}
+
+	t := &tappedConn{Conn: conn, captures: captures, ids: make([]uint64, len(captures))}
+	for i, c := range captures {
+		t.ids[i] = c.conns.Add(1)
+	}
+	t.record(&Event{Type: EventOpen, Client: client, Server: server})
+	return t
+}
+
+// tappedConn records the data sent over a connection
+type tappedConn struct {
+	net.Conn
+
+	captures []*Capture
+
+	// ids are the ids of the connection in each capture
+	ids []uint64
+
+	closeOnce sync.Once
+}
+
+// record sends e to every capture, with the connection's id in it
+func (t *tappedConn) record(e *Event) {
+	e.Time = time.Now()
+	for i, c := range t.captures {
+		e.Conn = t.ids[i]
+		c.record(e)
+	}
+}
+
+// Read implements net.Conn
+func (t *tappedConn) Read(b []byte) (int, error) {
+	n, err := t.Conn.Read(b)
+	if n > 0 {
+		t.record(&Event{Type: EventData, Direction: DirectionClient, Data: append([]byte(nil), b[:n]...)})
+	}
+	return n, err
+}
+
+// Write implements net.Conn
+func (t *tappedConn) Write(b []byte) (int, error) {
+	n, err := t.Conn.Write(b)
+	if n > 0 {
+		t.record(&Event{Type: EventData, Direction: DirectionServer, Data: append([]byte(nil), b[:n]...)})
+	}
+	return n, err
+}
+
+// Close implements net.Conn
+func (t *tappedConn) Close() error {
+	t.closeOnce.Do(func() { t.record(&Event{Type: EventClose}) })
+	return t.Conn.Close()
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package capture.
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestRegistry_Tap(t *testing.T) {
+	r := NewRegistry()
+	c := r.Start("default/app", 8080, 0)
+	other := r.Start("default/app", 9090, 0)
+	defer r.Stop(c)
+	defer r.Stop(other)
+
+	client, server := net.Pipe()
+	conn := r.Tap("default/app", 8080, server, "10.0.0.1:1234", "127.0.0.1:8080")
+
+	go func() {
+		client.Write([]byte("ping"))         //nolint:errcheck // Why: read below
+		io.ReadFull(client, make([]byte, 4)) //nolint:errcheck // Why: written below
+		client.Close()
+	}()
+	if _, err := io.ReadFull(conn, make([]byte, 4)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write([]byte("pong")); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	expected := []Event{
+		{Type: EventOpen, Conn: 1, Client: "10.0.0.1:1234", Server: "127.0.0.1:8080"},
+		{Type: EventData, Conn: 1, Direction: DirectionClient, Data: []byte("ping")},
+		{Type: EventData, Conn: 1, Direction: DirectionServer, Data: []byte("pong")},
+		{Type: EventClose, Conn: 1},
+	}
+	got := make([]Event, 0, len(expected))
+	for range expected {
+		got = append(got, <-c.Events())
+	}
+	if diff := cmp.Diff(expected, got, cmpopts.IgnoreFields(Event{}, "Time")); diff != "" {
+		t.Errorf("Tap() events mismatch (-want +got):\n%s", diff)
+	}
+	if len(other.Events()) != 0 {
+		t.Error("Tap() recorded events for another port")
+	}
+
+	if untapped := r.Tap("default/other", 8080, server, "", ""); untapped != server {
+		t.Error("Tap() wrapped a connection nothing captures")
+	}
+}
+
+func TestCapture_MaxBytes(t *testing.T) {
+	r := NewRegistry()
+	c := r.Start("default/app", 0, 4)
+	defer r.Stop(c)
+
+	c.record(&Event{Type: EventData, Data: []byte("ping")})
+	c.record(&Event{Type: EventData, Data: []byte("pong")})
+
+	select {
+	case <-c.Done():
+	default:
+		t.Fatal("capture didn't stop after reaching max bytes")
+	}
+	if len(c.Events()) != 1 {
+		t.Errorf("capture recorded %d events, expected 1", len(c.Events()))
+	}
+}
+
+func TestPcapngWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewPcapngWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for _, e := range []Event{
+		{Time: now, Type: EventOpen, Conn: 1, Client: "127.0.0.1:1234", Server: "127.0.0.1:8080"},
+		{Time: now, Type: EventData, Conn: 1, Direction: DirectionClient, Data: []byte("ping")},
+		{Time: now, Type: EventData, Conn: 1, Direction: DirectionServer, Data: []byte("pong!")},
+		{Time: now, Type: EventClose, Conn: 1},
+	} {
+		if err := w.Write(&e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// parse the blocks, checking that every packet has valid checksums
+	types := []uint32{}
+	payloads := []string{}
+	for b := buf.Bytes(); len(b) != 0; {
+		blockType := binary.LittleEndian.Uint32(b)
+		length := binary.LittleEndian.Uint32(b[4:])
+		if binary.LittleEndian.Uint32(b[length-4:]) != length {
+			t.Fatalf("block %d has mismatched lengths", len(types))
+		}
+		types = append(types, blockType)
+
+		if blockType == pcapngEnhancedPacket {
+			packet := b[28 : 28+binary.LittleEndian.Uint32(b[20:])]
+			if checksum(packet[:20], 0) != 0 {
+				t.Errorf("packet %d has an invalid IP checksum", len(types))
+			}
+
+			tcp := packet[20:]
+			pseudo := append(append([]byte{}, packet[12:20]...), 0, 6, 0, byte(len(tcp)))
+			if checksum(tcp, sum(pseudo)) != 0 {
+				t.Errorf("packet %d has an invalid TCP checksum", len(types))
+			}
+			if len(tcp) > 20 {
+				payloads = append(payloads, string(tcp[20:]))
+			}
+		}
+		b = b[length:]
+	}
+
+	expected := []uint32{pcapngSectionHeader, pcapngInterface}
+	for range 3 + 2 + 3 {
+		expected = append(expected, pcapngEnhancedPacket)
+	}
+	if diff := cmp.Diff(expected, types); diff != "" {
+		t.Errorf("NewPcapngWriter() blocks mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"ping", "pong!"}, payloads); diff != "" {
+		t.Errorf("NewPcapngWriter() payloads mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	// echoes everything back
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn) //nolint:errcheck // Why: test
+			}()
+		}
+	}()
+
+	buf := &bytes.Buffer{}
+	w := NewJSONLWriter(buf)
+	for _, e := range []Event{
+		{Type: EventOpen, Conn: 1, Client: "127.0.0.1:1234", Server: l.Addr().String()},
+		{Type: EventOpen, Conn: 2, Client: "127.0.0.1:1235", Server: l.Addr().String()},
+		{Type: EventData, Conn: 1, Direction: DirectionClient, Data: []byte("ping")},
+		{Type: EventData, Conn: 2, Direction: DirectionClient, Data: []byte("ping")},
+		{Type: EventData, Conn: 1, Direction: DirectionServer, Data: []byte("ping")},
+		{Type: EventData, Conn: 2, Direction: DirectionServer, Data: []byte("pong")},
+		{Type: EventClose, Conn: 1},
+		{Type: EventClose, Conn: 2},
+	} {
+		if err := w.Write(&e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Replay(context.Background(), NewJSONLReader(buf), &ReplayOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ReplayResult{
+		{Conn: 1, Sent: 4, Received: 4, Matched: true},
+		{Conn: 2, Sent: 4, Received: 4, Matched: false},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Replay() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains a writer for the pcapng format.
+package capture
+
+import (
+	"encoding/binary"
+	"io"
+	"net/netip"
+
+	"github.com/pkg/errors"
+)
+
+// This block contains the parts of the pcapng format that are used, see
+// https://www.ietf.org/archive/id/draft-ietf-opsawg-pcapng-01.html
+const (
+	pcapngSectionHeader     = 0x0A0D0D0A
+	pcapngInterface         = 0x00000001
+	pcapngEnhancedPacket    = 0x00000006
+	pcapngByteOrderMagic    = 0x1A2B3C4D
+	pcapngLinkTypeRaw       = 101
+	pcapngMaxSegmentPayload = 65535 - 60 - 20
+)
+
+// This block contains the TCP flags set on synthesized packets
+const (
+	tcpFIN = 0x01
+	tcpSYN = 0x02
+	tcpPSH = 0x08
+	tcpACK = 0x10
+)
+
+// PcapngWriter writes events as a pcapng file that can be opened with
+// Wireshark. Tunnels don't see the packets of a connection, so a TCP
+// handshake, segments and teardown are synthesized from the events.
+type PcapngWriter struct {
+	w     io.Writer
+	conns map[uint64]*pcapngConn
+}
+
+// pcapngConn is the state of a connection in a pcapng file
+type pcapngConn struct {
+	client, server netip.AddrPort
+
+	// clientSeq and serverSeq are the next sequence number of each side
+	clientSeq, serverSeq uint32
+}
+
+// NewPcapngWriter creates a new PcapngWriter, writing the file's header
+func NewPcapngWriter(w io.Writer) (*PcapngWriter, error) {
+	p := &PcapngWriter{w: w, conns: make(map[uint64]*pcapngConn)}
+
+	shb := make([]byte, 16)
+	binary.LittleEndian.PutUint32(shb[0:], pcapngByteOrderMagic)
+	binary.LittleEndian.PutUint16(shb[4:], 1)
+	binary.LittleEndian.PutUint16(shb[6:], 0)
+	binary.LittleEndian.PutUint64(shb[8:], 0xFFFFFFFFFFFFFFFF) // unknown section length
+	if err := p.writeBlock(pcapngSectionHeader, shb); err != nil {
+		return nil, err
+	}
+
+	idb := make([]byte, 8)
+	binary.LittleEndian.PutUint16(idb[0:], pcapngLinkTypeRaw)
+	if err := p.writeBlock(pcapngInterface, idb); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Write implements Writer
+func (p *PcapngWriter) Write(e *Event) error {
+	switch e.Type {
+	case EventOpen:
+		c := &pcapngConn{clientSeq: 1, serverSeq: 1}
+		c.client, c.server = pcapngEndpoints(e)
+		p.conns[e.Conn] = c
+
+		// the handshake uses the sequence numbers before the first byte
+		c.clientSeq--
+		c.serverSeq--
+		if err := p.writeSegment(e, c, DirectionClient, tcpSYN, nil); err != nil {
+			return err
+		}
+		if err := p.writeSegment(e, c, DirectionServer, tcpSYN|tcpACK, nil); err != nil {
+			return err
+		}
+		return p.writeSegment(e, c, DirectionClient, tcpACK, nil)
+	case EventData:
+		c, ok := p.conns[e.Conn]
+		if !ok {
+			return nil
+		}
+
+		for data := e.Data; len(data) != 0; {
+			n := min(len(data), pcapngMaxSegmentPayload)
+			if err := p.writeSegment(e, c, e.Direction, tcpPSH|tcpACK, data[:n]); err != nil {
+				return err
+			}
+			data = data[n:]
+		}
+	case EventClose:
+		c, ok := p.conns[e.Conn]
+		if !ok {
+			return nil
+		}
+		delete(p.conns, e.Conn)
+
+		if err := p.writeSegment(e, c, DirectionClient, tcpFIN|tcpACK, nil); err != nil {
+			return err
+		}
+		if err := p.writeSegment(e, c, DirectionServer, tcpFIN|tcpACK, nil); err != nil {
+			return err
+		}
+		return p.writeSegment(e, c, DirectionClient, tcpACK, nil)
+	}
+	return nil
+}
+
+// pcapngEndpoints returns the addresses of a connection. Addresses that
+// aren't IP addresses, e.g. Unix sockets, are replaced with made up ones.
+func pcapngEndpoints(e *Event) (client, server netip.AddrPort) {
+	client, cerr := netip.ParseAddrPort(e.Client)
+	server, serr := netip.ParseAddrPort(e.Server)
+	if cerr == nil && serr == nil && client.Addr().Unmap().Is4() == server.Addr().Unmap().Is4() {
+		return netip.AddrPortFrom(client.Addr().Unmap(), client.Port()),
+			netip.AddrPortFrom(server.Addr().Unmap(), server.Port())
+	}
+
+	// nolint: gosec // Why: wraps around, only used to tell connections apart
+	port := uint16(10000 + e.Conn%50000)
+	return netip.AddrPortFrom(netip.AddrFrom4([4]byte{10, 0, 0, 1}), port),
+		netip.AddrPortFrom(netip.AddrFrom4([4]byte{10, 0, 0, 2}), server.Port())
+}
+
+// writeSegment writes a TCP segment sent by one side of a connection,
+// advancing its sequence number
+func (p *PcapngWriter) writeSegment(e *Event, c *pcapngConn, dir Direction, flags byte, payload []byte) error {
+	src, dst, seq, ack := c.client, c.server, &c.clientSeq, c.serverSeq
+	if dir == DirectionServer {
+		src, dst, seq, ack = c.server, c.client, &c.serverSeq, c.clientSeq
+	}
+
+	tcp := make([]byte, 20+len(payload))
+	binary.BigEndian.PutUint16(tcp[0:], src.Port())
+	binary.BigEndian.PutUint16(tcp[2:], dst.Port())
+	binary.BigEndian.PutUint32(tcp[4:], *seq)
+	if flags&tcpACK != 0 {
+		binary.BigEndian.PutUint32(tcp[8:], ack)
+	}
+	tcp[12] = 5 << 4 // data offset, in 32-bit words
+	tcp[13] = flags
+	binary.BigEndian.PutUint16(tcp[14:], 65535) // window
+	copy(tcp[20:], payload)
+
+	// nolint: gosec // Why: segments are smaller than the max uint32
+	*seq += uint32(len(payload))
+	if flags&(tcpSYN|tcpFIN) != 0 {
+		*seq++
+	}
+
+	var packet []byte
+	if src.Addr().Is4() {
+		packet = make([]byte, 20, 20+len(tcp))
+		packet[0] = 0x45 // version 4, 5 32-bit words
+		// nolint: gosec // Why: segments are limited to pcapngMaxSegmentPayload
+		binary.BigEndian.PutUint16(packet[2:], uint16(20+len(tcp)))
+		packet[8] = 64 // ttl
+		packet[9] = 6  // tcp
+		srcIP, dstIP := src.Addr().As4(), dst.Addr().As4()
+		copy(packet[12:], srcIP[:])
+		copy(packet[16:], dstIP[:])
+		binary.BigEndian.PutUint16(packet[10:], checksum(packet, 0))
+	} else {
+		packet = make([]byte, 40, 40+len(tcp))
+		packet[0] = 0x60 // version 6
+		// nolint: gosec // Why: segments are limited to pcapngMaxSegmentPayload
+		binary.BigEndian.PutUint16(packet[4:], uint16(len(tcp)))
+		packet[6] = 6  // tcp
+		packet[7] = 64 // hop limit
+		srcIP, dstIP := src.Addr().As16(), dst.Addr().As16()
+		copy(packet[8:], srcIP[:])
+		copy(packet[24:], dstIP[:])
+	}
+
+	// the TCP checksum covers a pseudo header of the addresses, protocol
+	// and length
+	srcIP, dstIP := src.Addr().AsSlice(), dst.Addr().AsSlice()
+	pseudo := make([]byte, 0, 2*len(srcIP)+4)
+	pseudo = append(pseudo, srcIP...)
+	pseudo = append(pseudo, dstIP...)
+	// nolint: gosec // Why: segments are limited to pcapngMaxSegmentPayload
+	pseudo = binary.BigEndian.AppendUint16(append(pseudo, 0, 6), uint16(len(tcp)))
+	binary.BigEndian.PutUint16(tcp[16:], checksum(tcp, sum(pseudo)))
+
+	return p.writePacket(e, append(packet, tcp...))
+}
+
+// writePacket writes an enhanced packet block
+func (p *PcapngWriter) writePacket(e *Event, packet []byte) error {
+	// timestamps default to microseconds
+	// nolint: gosec // Why: times are after the epoch
+	ts := uint64(e.Time.UnixMicro())
+
+	body := make([]byte, 20, 20+len(packet)+3)
+	binary.LittleEndian.PutUint32(body[0:], 0) // interface
+	binary.LittleEndian.PutUint32(body[4:], uint32(ts>>32))
+	binary.LittleEndian.PutUint32(body[8:], uint32(ts))
+	// nolint: gosec // Why: packets are smaller than the max uint32
+	binary.LittleEndian.PutUint32(body[12:], uint32(len(packet)))
+	// nolint: gosec // Why: packets are smaller than the max uint32
+	binary.LittleEndian.PutUint32(body[16:], uint32(len(packet)))
+	body = append(body, packet...)
+	return p.writeBlock(pcapngEnhancedPacket, body)
+}
+
+// writeBlock writes a block, padding its body to 32 bits
+func (p *PcapngWriter) writeBlock(blockType uint32, body []byte) error {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+
+	// nolint: gosec // Why: blocks are smaller than the max uint32
+	length := uint32(12 + len(body))
+	b := make([]byte, 0, length)
+	b = binary.LittleEndian.AppendUint32(b, blockType)
+	b = binary.LittleEndian.AppendUint32(b, length)
+	b = append(b, body...)
+	b = binary.LittleEndian.AppendUint32(b, length)
+
+	_, err := p.w.Write(b)
+	return errors.Wrap(err, "failed to write pcapng block")
+}
+
+// sum returns the one's complement sum of b, as used by IP checksums
+func sum(b []byte) uint32 {
+	var s uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		s += uint32(binary.BigEndian.Uint16(b[i:]))
+	}
+	if len(b)%2 == 1 {
+		s += uint32(b[len(b)-1]) << 8
+	}
+	return s
+}
+
+// checksum returns the IP checksum of b, starting from initial
+func checksum(b []byte, initial uint32) uint16 {
+	s := initial + sum(b)
+	for s>>16 != 0 {
+		s = s&0xFFFF + s>>16
+	}
+	// nolint: gosec // Why: folded into 16 bits above
+	return ^uint16(s)
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the replaying of recorded connections.
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// replayCloseTimeout is how long to wait for the server to send the rest
+// of its response after a replayed connection was closed
+const replayCloseTimeout = 5 * time.Second
+
+// JSONLReader reads the events written by a JSONLWriter
+type JSONLReader struct {
+	dec *json.Decoder
+}
+
+// NewJSONLReader creates a new JSONLReader
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	return &JSONLReader{dec: json.NewDecoder(r)}
+}
+
+// Read returns the next event, or io.EOF once every event was read
+func (r *JSONLReader) Read() (*Event, error) {
+	var e Event
+	if err := r.dec.Decode(&e); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, errors.Wrap(err, "failed to decode event")
+	}
+	return &e, nil
+}
+
+// ReplayOptions configures Replay
+type ReplayOptions struct {
+	// Target is the address connections are replayed to, defaults to the
+	// server they were recorded with
+	Target string
+
+	// Timing sends the data with the delays it was recorded with, rather
+	// than as fast as possible
+	Timing bool
+}
+
+// ReplayResult is the outcome of replaying a connection
+type ReplayResult struct {
+	// Conn is the id of the connection in the capture
+	Conn uint64
+
+	// Sent and Received are the bytes sent to, and received from, the
+	// target
+	Sent     int64
+	Received int64
+
+	// Matched is true if the target responded with exactly the data that
+	// was recorded
+	Matched bool
+
+	// Err is set if the connection failed
+	Err error
+}
+
+// replayConn is a connection being replayed
+type replayConn struct {
+	result ReplayResult
+	conn   net.Conn
+
+	// expected is what the server sent when it was recorded
+	expected bytes.Buffer
+
+	// received is what the target sent, it's only safe to read once done
+	// is closed
+	received bytes.Buffer
+	done     chan struct{}
+}
+
+// Replay opens the connections read from r again, sending the data their
+// clients sent to the target, and compares the responses with the
+// recorded ones. A result is returned for every connection, in the
+// order they were opened.
+func Replay(ctx context.Context, r *JSONLReader, opts *ReplayOptions) ([]ReplayResult, error) { //nolint:funlen // Why: it's a single loop over the events
+	conns := make(map[uint64]*replayConn)
+	order := make([]*replayConn, 0)
+	wg := sync.WaitGroup{}
+	defer func() {
+		// connections are only closed once they're finished, or when the
+		// replay is stopped early
+		for _, c := range conns {
+			if c.conn != nil {
+				c.conn.Close()
+			}
+		}
+		wg.Wait()
+	}()
+
+	dialer := &net.Dialer{}
+	var start, first time.Time
+	for {
+		e, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if opts.Timing {
+			if first.IsZero() {
+				start, first = time.Now(), e.Time
+			}
+			select {
+			case <-time.After(time.Until(start.Add(e.Time.Sub(first)))):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		switch e.Type {
+		case EventOpen:
+			c := &replayConn{result: ReplayResult{Conn: e.Conn}, done: make(chan struct{})}
+			conns[e.Conn] = c
+			order = append(order, c)
+
+			target := opts.Target
+			if target == "" {
+				target = e.Server
+			}
+			c.conn, c.result.Err = dialer.DialContext(ctx, "tcp", target)
+			if c.result.Err != nil {
+				close(c.done)
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(c.done)
+				c.result.Received, _ = io.Copy(&c.received, c.conn) //nolint:errcheck // Why: the response is compared instead
+			}()
+		case EventData:
+			c, ok := conns[e.Conn]
+			if !ok {
+				return nil, fmt.Errorf("data sent over connection %d before it was opened", e.Conn)
+			}
+			if c.conn == nil {
+				continue
+			}
+
+			if e.Direction == DirectionServer {
+				c.expected.Write(e.Data)
+				continue
+			}
+			if c.result.Err == nil {
+				n, err := c.conn.Write(e.Data)
+				c.result.Sent += int64(n)
+				c.result.Err = errors.Wrap(err, "failed to send data")
+			}
+		case EventClose:
+			if c, ok := conns[e.Conn]; ok && c.conn != nil {
+				// give the server time to respond to the last request
+				closeWrite(c.conn)
+				c.conn.SetReadDeadline(time.Now().Add(replayCloseTimeout)) //nolint:errcheck // Why: only guards against hangs
+			}
+		}
+	}
+
+	// connections that weren't closed when the capture stopped are closed
+	// once the server stops responding
+	for _, c := range order {
+		if c.conn != nil {
+			c.conn.SetReadDeadline(time.Now().Add(replayCloseTimeout)) //nolint:errcheck // Why: only guards against hangs
+		}
+	}
+
+	results := make([]ReplayResult, 0, len(order))
+	for _, c := range order {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		c.result.Matched = c.result.Err == nil && bytes.Equal(c.expected.Bytes(), c.received.Bytes())
+		results = append(results, c.result)
+	}
+	return results, nil
+}
+
+// closeWrite closes the sending side of conn, or the whole connection if
+// that isn't supported
+func closeWrite(conn net.Conn) {
+	if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite() //nolint:errcheck // Why: best effort
+		return
+	}
+	conn.Close()
+}
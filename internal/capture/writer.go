@@ -0,0 +1,53 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the formats captures are written in.
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Writer writes the events of a capture to a file
+type Writer interface {
+	// Write writes an event
+	Write(e *Event) error
+}
+
+// NewWriter creates a writer for a format, either jsonl or pcapng
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case "jsonl":
+		return NewJSONLWriter(w), nil
+	case "pcapng":
+		return NewPcapngWriter(w)
+	}
+	return nil, fmt.Errorf("unknown capture format %q, expected jsonl or pcapng", format)
+}
+
+// FormatFromPath returns the format of a capture file based on its
+// extension, defaulting to jsonl
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".pcapng") {
+		return "pcapng"
+	}
+	return "jsonl"
+}
+
+// JSONLWriter writes each event as a line of JSON, data is base64 encoded
+type JSONLWriter struct {
+	enc *json.Encoder
+}
+
+// NewJSONLWriter creates a new JSONLWriter
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	return &JSONLWriter{enc: json.NewEncoder(w)}
+}
+
+// Write implements Writer
+func (w *JSONLWriter) Write(e *Event) error {
+	return w.enc.Encode(e)
+}
@@ -0,0 +1,7 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package envvars.
+
+// Package envvars has helpers for generating Kubernetes style service
+// environment variables.
+package envvars
@@ -0,0 +1,95 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package envvars.
+package envvars
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Port is a port exposed by a service
+type Port struct {
+	// Name is the name of the service port, if it has one
+	Name string
+
+	// Port is the port exposed by the service in the cluster
+	Port uint
+
+	// LocalPort is the port to use to reach the service, this may
+	// differ from Port if it was remapped locally
+	LocalPort uint
+
+	// Protocol is the protocol of this port, defaults to TCP
+	Protocol string
+}
+
+// Service is a service that environment variables should be created for
+type Service struct {
+	// Name is the name of the service
+	Name string
+
+	// Host is the host, generally an IP address, to use to reach this
+	// service
+	Host string
+
+	// Ports are the ports exposed by this service
+	Ports []Port
+}
+
+// makeEnvVariableName converts a service, or port, name into the
+// format used by Kubernetes for environment variables.
+func makeEnvVariableName(str string) string {
+	return strings.ToUpper(strings.ReplaceAll(str, "-", "_"))
+}
+
+// FromService creates the environment variables Kubernetes would inject
+// into a pod for the provided service. This mirrors the kubelet, see:
+// https://kubernetes.io/docs/concepts/containers/container-environment/#cluster-information
+func FromService(s *Service) map[string]string {
+	vars := make(map[string]string)
+	if s.Host == "" || len(s.Ports) == 0 {
+		return vars
+	}
+
+	name := makeEnvVariableName(s.Name)
+
+	// Service variables, e.g. REDIS_SERVICE_HOST
+	vars[name+"_SERVICE_HOST"] = s.Host
+	vars[name+"_SERVICE_PORT"] = strconv.FormatUint(uint64(s.Ports[0].LocalPort), 10)
+	for i := range s.Ports {
+		if s.Ports[i].Name == "" {
+			continue
+		}
+
+		key := fmt.Sprintf("%s_SERVICE_PORT_%s", name, makeEnvVariableName(s.Ports[i].Name))
+		vars[key] = strconv.FormatUint(uint64(s.Ports[i].LocalPort), 10)
+	}
+
+	// Docker link variables, e.g. REDIS_PORT_6379_TCP_ADDR. These are
+	// keyed by the service port, but point to the local port.
+	for i := range s.Ports {
+		p := &s.Ports[i]
+
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = "TCP"
+		}
+
+		url := fmt.Sprintf("%s://%s", strings.ToLower(protocol),
+			net.JoinHostPort(s.Host, strconv.FormatUint(uint64(p.LocalPort), 10)))
+		if i == 0 {
+			vars[name+"_PORT"] = url
+		}
+
+		prefix := fmt.Sprintf("%s_PORT_%d_%s", name, p.Port, strings.ToUpper(protocol))
+		vars[prefix] = url
+		vars[prefix+"_PROTO"] = strings.ToLower(protocol)
+		vars[prefix+"_PORT"] = strconv.FormatUint(uint64(p.LocalPort), 10)
+		vars[prefix+"_ADDR"] = s.Host
+	}
+
+	return vars
+}
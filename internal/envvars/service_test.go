@@ -0,0 +1,63 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package envvars.
+package envvars
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFromService(t *testing.T) {
+	vars := FromService(&Service{
+		Name: "my-postgres",
+		Host: "127.0.0.2",
+		Ports: []Port{
+			{Name: "postgres", Port: 5432, LocalPort: 5432},
+			{Name: "metrics", Port: 9090, LocalPort: 49152},
+		},
+	})
+
+	expected := map[string]string{
+		"MY_POSTGRES_SERVICE_HOST":          "127.0.0.2",
+		"MY_POSTGRES_SERVICE_PORT":          "5432",
+		"MY_POSTGRES_SERVICE_PORT_POSTGRES": "5432",
+		"MY_POSTGRES_SERVICE_PORT_METRICS":  "49152",
+		"MY_POSTGRES_PORT":                  "tcp://127.0.0.2:5432",
+		"MY_POSTGRES_PORT_5432_TCP":         "tcp://127.0.0.2:5432",
+		"MY_POSTGRES_PORT_5432_TCP_PROTO":   "tcp",
+		"MY_POSTGRES_PORT_5432_TCP_PORT":    "5432",
+		"MY_POSTGRES_PORT_5432_TCP_ADDR":    "127.0.0.2",
+		"MY_POSTGRES_PORT_9090_TCP":         "tcp://127.0.0.2:49152",
+		"MY_POSTGRES_PORT_9090_TCP_PROTO":   "tcp",
+		"MY_POSTGRES_PORT_9090_TCP_PORT":    "49152",
+		"MY_POSTGRES_PORT_9090_TCP_ADDR":    "127.0.0.2",
+	}
+	if diff := cmp.Diff(expected, vars); diff != "" {
+		t.Errorf("unexpected variables (-want +got):\n%s", diff)
+	}
+}
+
+func TestFromService_IPv6(t *testing.T) {
+	vars := FromService(&Service{
+		Name:  "redis",
+		Host:  "fd00::2",
+		Ports: []Port{{Port: 6379, LocalPort: 6379}},
+	})
+
+	if got := vars["REDIS_PORT"]; got != "tcp://[fd00::2]:6379" {
+		t.Errorf("expected REDIS_PORT to be tcp://[fd00::2]:6379, got %q", got)
+	}
+}
+
+func TestFromService_NoAddress(t *testing.T) {
+	vars := FromService(&Service{
+		Name:  "redis",
+		Ports: []Port{{Port: 6379, LocalPort: 6379}},
+	})
+
+	if len(vars) != 0 {
+		t.Errorf("expected no variables for a service without an address, got %v", vars)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains functions for pausing the autoscalers of exposed workloads.
+package expose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// KEDAPausedReplicasAnnotation pauses a KEDA ScaledObject at the given
+	// number of replicas
+	KEDAPausedReplicasAnnotation = "autoscaling.keda.sh/paused-replicas"
+
+	// pausedTargetPrefix is prefixed to the scale target of paused HPAs,
+	// since they have no way of being paused otherwise
+	pausedTargetPrefix = "localizer-paused-"
+)
+
+// autoscalerKind is the type of an autoscaler
+type autoscalerKind string
+
+const (
+	// autoscalerHPA is a HorizontalPodAutoscaler
+	autoscalerHPA autoscalerKind = "HorizontalPodAutoscaler"
+
+	// autoscalerKEDA is a KEDA ScaledObject
+	autoscalerKEDA autoscalerKind = "ScaledObject"
+)
+
+// kedaScaledObjects is the resource of KEDA ScaledObjects
+var kedaScaledObjects = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledobjects"}
+
+// pausedAutoscaler is an autoscaler targeting a workload that is paused
+// while the workload is scaled down, so that it doesn't scale it back up.
+type pausedAutoscaler struct {
+	Kind autoscalerKind `json:"kind"`
+	Name string         `json:"name"`
+
+	// Target, MinReplicas and MaxReplicas are the scale target and bounds
+	// of a HPA at the time it was paused
+	Target      string `json:"target,omitempty"`
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	MaxReplicas int32  `json:"maxReplicas,omitempty"`
+
+	// PausedReplicas is the value of the KEDA paused replicas annotation
+	// at the time it was paused, if it was set
+	PausedReplicas *string `json:"pausedReplicas,omitempty"`
+}
+
+// findAutoscalers returns the HPAs and KEDA ScaledObjects targeting an
+// owner. HPAs created by KEDA are ignored, since KEDA manages them.
+func (c *Client) findAutoscalers(ctx context.Context, owner *kube.Owner) ([]pausedAutoscaler, error) {
+	autoscalers := make([]pausedAutoscaler, 0)
+
+	hpas, err := c.k.AutoscalingV2().HorizontalPodAutoscalers(owner.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list horizontal pod autoscalers")
+	}
+	for i := range hpas.Items {
+		hpa := &hpas.Items[i]
+		if !targetsOwner(owner, hpa.Spec.ScaleTargetRef.APIVersion, hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name) {
+			continue
+		}
+
+		if ref := metav1.GetControllerOfNoCopy(hpa); ref != nil && ref.Kind == string(autoscalerKEDA) {
+			continue
+		}
+
+		autoscalers = append(autoscalers, pausedAutoscaler{
+			Kind:        autoscalerHPA,
+			Name:        hpa.Name,
+			Target:      hpa.Spec.ScaleTargetRef.Name,
+			MinReplicas: hpa.Spec.MinReplicas,
+			MaxReplicas: hpa.Spec.MaxReplicas,
+		})
+	}
+
+	// KEDA is optional, so only look for ScaledObjects if it's installed
+	_, err = c.rm.RESTMapping(schema.GroupKind{Group: kedaScaledObjects.Group, Kind: string(autoscalerKEDA)}, kedaScaledObjects.Version)
+	if meta.IsNoMatchError(err) {
+		return autoscalers, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to check if KEDA is installed")
+	}
+
+	scaledObjects, err := c.dyn.Resource(kedaScaledObjects).Namespace(owner.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list KEDA scaled objects")
+	}
+	for i := range scaledObjects.Items {
+		so := &scaledObjects.Items[i]
+		apiVersion, _, _ := unstructured.NestedString(so.Object, "spec", "scaleTargetRef", "apiVersion") //nolint:errcheck // Why: empty is the default
+		kind, _, _ := unstructured.NestedString(so.Object, "spec", "scaleTargetRef", "kind")             //nolint:errcheck // Why: empty is the default
+		name, _, _ := unstructured.NestedString(so.Object, "spec", "scaleTargetRef", "name")             //nolint:errcheck // Why: empty is the default
+
+		// KEDA defaults to targeting deployments
+		if apiVersion == "" {
+			apiVersion = "apps/v1"
+		}
+		if kind == "" {
+			kind = "Deployment"
+		}
+
+		if !targetsOwner(owner, apiVersion, kind, name) {
+			continue
+		}
+
+		autoscaler := pausedAutoscaler{Kind: autoscalerKEDA, Name: so.GetName()}
+		if v, ok := so.GetAnnotations()[KEDAPausedReplicasAnnotation]; ok {
+			autoscaler.PausedReplicas = &v
+		}
+		autoscalers = append(autoscalers, autoscaler)
+	}
+
+	return autoscalers, nil
+}
+
+// targetsOwner returns true if a scale target reference points to the
+// given owner
+func targetsOwner(owner *kube.Owner, apiVersion, kind, name string) bool {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return false
+	}
+	return gv.Group == owner.Resource.Group && kind == owner.Kind && name == owner.Name
+}
+
+// pauseAutoscaler stops an autoscaler from scaling its target. HPAs can't
+// be paused, so their scale target is pointed at a workload that doesn't
+// exist instead.
+func (c *Client) pauseAutoscaler(ctx context.Context, namespace string, a *pausedAutoscaler) error {
+	log := c.log.WithField("autoscaler", fmt.Sprintf("%s/%s/%s", a.Kind, namespace, a.Name))
+
+	switch a.Kind {
+	case autoscalerHPA:
+		log.Info("pausing horizontal pod autoscaler")
+		return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			hpa, err := c.k.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, a.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			hpa.Spec.ScaleTargetRef.Name = pausedTargetPrefix + a.Target
+			_, err = c.k.AutoscalingV2().HorizontalPodAutoscalers(namespace).Update(ctx, hpa, metav1.UpdateOptions{})
+			return err
+		})
+	case autoscalerKEDA:
+		log.Info("pausing KEDA scaled object")
+		return c.updateScaledObject(ctx, namespace, a.Name, func(annotations map[string]string) {
+			annotations[KEDAPausedReplicasAnnotation] = "0"
+		})
+	}
+
+	return fmt.Errorf("unknown autoscaler kind %q", a.Kind)
+}
+
+// resumeAutoscaler undoes pauseAutoscaler, restoring the autoscaler to the
+// state it was in when it was paused
+func (c *Client) resumeAutoscaler(ctx context.Context, namespace string, a *pausedAutoscaler) error {
+	log := c.log.WithField("autoscaler", fmt.Sprintf("%s/%s/%s", a.Kind, namespace, a.Name))
+
+	var err error
+	switch a.Kind {
+	case autoscalerHPA:
+		log.Info("resuming horizontal pod autoscaler")
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			hpa, err := c.k.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, a.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			// don't overwrite changes made to the HPA while it was paused
+			if hpa.Spec.ScaleTargetRef.Name != pausedTargetPrefix+a.Target {
+				log.Warn("horizontal pod autoscaler was modified while paused, not restoring it")
+				return nil
+			}
+
+			hpa.Spec.ScaleTargetRef.Name = a.Target
+			hpa.Spec.MinReplicas = a.MinReplicas
+			hpa.Spec.MaxReplicas = a.MaxReplicas
+			_, err = c.k.AutoscalingV2().HorizontalPodAutoscalers(namespace).Update(ctx, hpa, metav1.UpdateOptions{})
+			return err
+		})
+	case autoscalerKEDA:
+		log.Info("resuming KEDA scaled object")
+		err = c.updateScaledObject(ctx, namespace, a.Name, func(annotations map[string]string) {
+			if a.PausedReplicas != nil {
+				annotations[KEDAPausedReplicasAnnotation] = *a.PausedReplicas
+			} else {
+				delete(annotations, KEDAPausedReplicasAnnotation)
+			}
+		})
+	default:
+		return fmt.Errorf("unknown autoscaler kind %q", a.Kind)
+	}
+
+	if kerrors.IsNotFound(err) {
+		log.Warn("autoscaler no longer exists, not restoring it")
+		return nil
+	}
+	return err
+}
+
+// updateScaledObject modifies the annotations of a KEDA ScaledObject
+func (c *Client) updateScaledObject(ctx context.Context, namespace, name string, fn func(map[string]string)) error {
+	client := c.dyn.Resource(kedaScaledObjects).Namespace(namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		so, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		annotations := so.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		fn(annotations)
+		so.SetAnnotations(annotations)
+
+		_, err = client.Update(ctx, so, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package expose.
+package expose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestClient_pauseAutoscaler_HPA(t *testing.T) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "app"},
+			MinReplicas:    ptr.To(int32(2)),
+			MaxReplicas:    10,
+		},
+	}
+	other := hpa.DeepCopy()
+	other.Name = "other"
+	other.Spec.ScaleTargetRef.Name = "other"
+
+	k := fake.NewClientset(hpa, other)
+	c := &Client{k: k, log: logrus.New(), rm: meta.NewDefaultRESTMapper(nil)}
+
+	autoscalers, err := c.findAutoscalers(context.Background(), &kube.Owner{
+		Resource:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		Kind:      "Deployment",
+		Namespace: "default",
+		Name:      "app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []pausedAutoscaler{{Kind: autoscalerHPA, Name: "app", Target: "app", MinReplicas: ptr.To(int32(2)), MaxReplicas: 10}}
+	if diff := cmp.Diff(expected, autoscalers); diff != "" {
+		t.Fatalf("findAutoscalers() mismatch (-want +got):\n%s", diff)
+	}
+
+	getHPA := func() *autoscalingv2.HorizontalPodAutoscaler {
+		hpa, err := k.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.Background(), "app", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hpa
+	}
+
+	if err := c.pauseAutoscaler(context.Background(), "default", &autoscalers[0]); err != nil {
+		t.Fatal(err)
+	}
+	if got := getHPA().Spec.ScaleTargetRef.Name; got != pausedTargetPrefix+"app" {
+		t.Errorf("pauseAutoscaler() scale target = %q", got)
+	}
+
+	if err := c.resumeAutoscaler(context.Background(), "default", &autoscalers[0]); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(hpa.Spec, getHPA().Spec); diff != "" {
+		t.Errorf("resumeAutoscaler() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -20,23 +20,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
 
+	"github.com/getoutreach/localizer/internal/capture"
 	"github.com/getoutreach/localizer/internal/kevents"
 	"github.com/getoutreach/localizer/internal/kube"
-	"github.com/getoutreach/localizer/internal/reflectconversions"
+	"github.com/getoutreach/localizer/internal/ssh"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/conversion"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/scale"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -48,10 +46,25 @@ type Client struct {
 	podStore cache.Store
 	svcStore cache.Store
 	rm       meta.RESTMapper
+	dyn      dynamic.Interface
+	scales   scale.ScalesGetter
+
+	// owner is the identity of the developer running localizer, used
+	// for expose sessions
+	owner string
+
+	// capture records the connections of exposes
+	capture *capture.Registry
+
+	// transport is the protocol port-forwards to expose pods are
+	// tunneled over
+	transport kube.PortForwardTransport
 }
 
-// NewExposer returns a new client capable of exposing localports to remote locations
-func NewExposer(k kubernetes.Interface, kconf *rest.Config, log logrus.FieldLogger) *Client {
+// NewExposer returns a new client capable of exposing localports to remote locations,
+// recording connections to registry if it's not nil
+func NewExposer(k kubernetes.Interface, kconf *rest.Config, log logrus.FieldLogger, registry *capture.Registry,
+	transport kube.PortForwardTransport) *Client {
 	return &Client{
 		k,
 		kconf,
@@ -59,9 +72,32 @@ func NewExposer(k kubernetes.Interface, kconf *rest.Config, log logrus.FieldLogg
 		nil,
 		nil,
 		nil,
+		nil,
+		nil,
+		sessionOwner(),
+		registry,
+		transport,
 	}
 }
 
+// withLog returns a copy of the client that logs to log, this is used to
+// send the progress of an expose to the user that requested it
+func (c *Client) withLog(log logrus.FieldLogger) *Client {
+	cp := *c
+	cp.log = log
+	return &cp
+}
+
+// ServiceOwners returns the workloads powering a service, found the same
+// way expose finds the workloads it disables, see kube.FindServiceOwners.
+// It can only be called once the client is started.
+func (c *Client) ServiceOwners(ctx context.Context, svc *corev1.Service) ([]kube.Owner, error) {
+	if c.dyn == nil {
+		return nil, fmt.Errorf("expose client isn't started")
+	}
+	return kube.FindServiceOwners(ctx, c.log, c.dyn, c.rm, svc)
+}
+
 // Start warms up the expose cache and enables running Expose()
 // among other things.
 func (c *Client) Start(ctx context.Context) error {
@@ -78,30 +114,55 @@ func (c *Client) Start(ctx context.Context) error {
 
 	c.rm = restmapper.NewDiscoveryRESTMapper(groupResources)
 
+	c.dyn, err = dynamic.NewForConfig(c.kconf)
+	if err != nil {
+		return errors.Wrap(err, "failed to create dynamic client")
+	}
+
+	c.scales, err = scale.NewForConfig(c.kconf, c.rm, dynamic.LegacyAPIPathResolverFunc,
+		scale.NewDiscoveryScaleKindResolver(c.k.Discovery()))
+	if err != nil {
+		return errors.Wrap(err, "failed to create scale client")
+	}
+
+	// recover sessions abandoned by a previous daemon, and those other
+	// developers abandoned long ago
+	c.gcSessions(ctx, true)
+	go c.gcSessionsLoop(ctx)
+
 	for _, obj := range c.podStore.List() {
 		p := obj.(*corev1.Pod)
 
-		if p.Labels[ExposedPodLabel] == "true" {
+		// pods belonging to a session are cleaned up with it, these are
+		// only pods created by older versions of localizer
+		if p.Labels[ExposedPodLabel] == "true" && p.Labels[SessionNameLabel] == "" {
 			key, _ := cache.MetaNamespaceKeyFunc(p) //nolint:errcheck // Why: key still returns
 			log := c.log.WithField("pod", key)
 			log.Warn("removing abandoned localizer pod")
 
+			if p.Labels[RoutePodLabel] != "" {
+				if err := c.restoreRoutedService(ctx, p); err != nil {
+					log.WithError(err).Warn("failed to restore routed service")
+				}
+			}
+
 			err := c.k.CoreV1().Pods(p.Namespace).Delete(ctx, p.Name, metav1.DeleteOptions{})
 			if err != nil {
 				log.WithError(err).Warn("failed to remove abandoned localizer pod")
 			}
 
-			var objects []scaledObjectType
+			var objects []disabledObject
 			err = json.Unmarshal([]byte(p.Annotations[ObjectsPodLabel]), &objects)
 			if err != nil {
 				c.log.WithError(err).Warn("failed to ensure controllers were scaled back up")
 				continue
 			}
 
-			for _, obj := range objects {
-				err := c.scaleObject(ctx, obj, obj.Replicas)
-				if err != nil {
-					c.log.WithError(err).WithField("object", obj.GetKey()).Warn("failed to restore controller scale")
+			for i := range objects {
+				obj := &objects[i]
+				obj.normalize()
+				if err := c.restoreObject(ctx, obj); err != nil {
+					c.log.WithError(err).WithField("object", obj.GetKey()).Warn("failed to restore controller")
 					continue
 				}
 			}
@@ -111,94 +172,9 @@ func (c *Client) Start(ctx context.Context) error {
 	return nil
 }
 
-func (c *Client) getMapping(obj interface{}) (*meta.RESTMapping, error) {
-	// We have to type switch because TypeMeta isn't being populated as it should
-	// be, so we have no way to get information on what type of object we're
-	// looking at without doing this.
-	kind := ""
-	switch obj.(type) {
-	case *appsv1.Deployment:
-		kind = "deployment"
-	case *appsv1.StatefulSet:
-		kind = "statefulset"
-	}
-
-	// hardcode appsv1 because we're doing that only in scaleObject right now
-	gk := schema.GroupKind{Group: "apps", Kind: kind}
-	c.log.WithField("group", gk).Debug("looking up group")
-	return c.rm.RESTMapping(gk, "v1")
-}
-
-type patchUInt32Value struct {
-	Op    string `json:"op"`
-	Path  string `json:"path"`
-	Value uint   `json:"value"`
-}
-
-// scaleObject attempts to scale a given object in appsv1
-func (c *Client) scaleObject(ctx context.Context, scaledObj scaledObjectType, replicas int) error {
-	payload := []patchUInt32Value{{
-		Op:   "replace",
-		Path: "/spec/replicas",
-		// nolint: gosec // Why: replicas is never negative
-		Value: uint(replicas),
-	}}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal scale patch body")
-	}
-
-	// TODO(jaredallard): build client from self link one day
-	req := c.k.AppsV1().RESTClient().Patch(types.JSONPatchType).Resource(scaledObj.Resource).
-		Namespace(scaledObj.GetNamespace()).Name(scaledObj.GetName()).Body(payloadBytes)
-
-	c.log.WithField("url", req.URL().String()).Debug("setting replicas")
-	res := req.Do(ctx)
-	if res.Error() != nil {
-		return res.Error()
-	}
-	return nil
-}
-
-// getReplicasFromObject uses reflect to extract the replicas from a given object
-// assumes path is Spec.Replicas
-func getReplicasFromObject(obj interface{}) (int, error) {
-	v, err := conversion.EnforcePtr(obj)
-	if err != nil {
-		return 0, err
-	}
-	err = reflectconversions.EnforceStruct(v)
-	if err != nil {
-		return 0, err
-	}
-
-	specField := v.FieldByName("Spec")
-	if !specField.IsValid() {
-		return 0, fmt.Errorf("struct lacks struct Spec")
-	}
-	err = reflectconversions.EnforceStruct(v)
-	if err != nil {
-		return 0, err
-	}
-
-	replicasField := specField.FieldByName("Replicas")
-	if !replicasField.IsValid() {
-		return 0, fmt.Errorf("struct lacks field replicas")
-	}
-	replicasV, err := conversion.EnforcePtr(replicasField.Interface())
-	if err != nil {
-		return 0, err
-	}
-	if replicasV.Kind() != reflect.Int32 {
-		return 0, fmt.Errorf("expected Replicas to be int32, but got %v: %v (%#v)", replicasV.Kind(), replicasV.Type(), replicasV.Interface())
-	}
-
-	return int(replicasV.Int()), nil
-}
-
 // getServiceControllers finds controllers that create pods for a given service
 // and returns them
-func (c *Client) getServiceControllers(_ context.Context, namespace, serviceName string) ([]scaledObjectType, error) {
+func (c *Client) getServiceControllers(ctx context.Context, namespace, serviceName string) ([]disabledObject, error) {
 	obj, exists, err := c.svcStore.GetByKey(fmt.Sprintf("%s/%s", namespace, serviceName))
 	if err != nil {
 		return nil, err
@@ -212,75 +188,144 @@ func (c *Client) getServiceControllers(_ context.Context, namespace, serviceName
 	}
 
 	c.log.WithField("service", fmt.Sprintf("%s/%s", namespace, serviceName)).Debug("finding controllers")
-	objs, err := kube.FindControllersForService(c.log, svc)
+	owners, err := kube.FindServiceOwners(ctx, c.log, c.dyn, c.rm, svc)
 	if err != nil {
 		return nil, err
 	}
 
-	scaledObjects := make([]scaledObjectType, 0)
-	for _, obj := range objs {
-		metaObj, err := meta.Accessor(obj)
+	objects := make([]disabledObject, 0)
+	for i := range owners {
+		o, err := c.newDisabledObject(ctx, svc, &owners[i])
 		if err != nil {
-			c.log.WithError(err).Warn("failed to handle object")
+			c.log.WithError(err).WithField("owner", owners[i].Kind+"/"+owners[i].Name).Warn("ignoring workload, it will keep receiving traffic")
 			continue
 		}
-		// Don't store the entire object
-		mobj := meta.AsPartialObjectMetadata(metaObj)
+		objects = append(objects, *o)
+	}
 
-		mapping, err := c.getMapping(obj)
-		if err != nil {
-			c.log.WithError(err).Warn("failed to get controller type")
-			continue
-		}
+	return objects, nil
+}
+
+// Expose exposed a port, localPort, on the local host, and opens a remote port
+// that can be accessed via the remote service at remotePort
+func (c *Client) Expose(ctx context.Context, ports []kube.ResolvedServicePort, namespace, serviceName string,
+	opts *ExposeOpts) (*ServiceForward, error) {
+	if opts == nil {
+		opts = &ExposeOpts{}
+	}
+
+	log := c.log
+	if opts.Log != nil {
+		log = opts.Log
+	}
+	log = log.WithField("service", fmt.Sprintf("%s/%s", namespace, serviceName))
+	c = c.withLog(log)
+
+	podOptions := opts.PodOptions
+	if podOptions == nil {
+		podOptions = DefaultPodOptions()
+	}
+
+	drainTimeout := opts.DrainTimeout
+	if drainTimeout == 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
 
-		scaledObject := scaledObjectType{
-			obj,
-			mobj,
-			0,
-			mapping.Resource.Resource,
+	var s *corev1.Service
+	if opts.Create {
+		if opts.Mode == ModeRoute {
+			return nil, fmt.Errorf("created services have no existing replicas to route to")
+		}
+		if len(ports) == 0 {
+			return nil, fmt.Errorf("at least one port is required to create a service")
 		}
 
-		replicas, err := getReplicasFromObject(obj)
+		// the service is only created once the session records it, see
+		// ServiceForward.Start
+		if err := c.checkServiceMissing(ctx, namespace, serviceName); err != nil {
+			return nil, err
+		}
+		s = &corev1.Service{Spec: corev1.ServiceSpec{Selector: createdServiceSelector(serviceName)}}
+	} else {
+		var err error
+		s, err = c.k.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
 		if err != nil {
-			c.log.WithError(err).Warn("failed to get replicas from object")
-			continue
+			return nil, err
 		}
-		scaledObject.Replicas = replicas
 
-		scaledObjects = append(scaledObjects, scaledObject)
+		if len(s.Spec.Selector) == 0 {
+			return nil, fmt.Errorf("headless services are not supported")
+		}
 	}
 
-	return scaledObjects, nil
-}
+	if opts.Mode == ModeRoute && len(opts.Rules) == 0 {
+		return nil, fmt.Errorf("at least one header or percentage rule is required to route a service")
+	}
 
-// Expose exposed a port, localPort, on the local host, and opens a remote port
-// that can be accessed via the remote service at remotePort
-func (c *Client) Expose(ctx context.Context, ports []kube.ResolvedServicePort, namespace, serviceName string) (*ServiceForward, error) {
-	s, err := c.k.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
+	// the router sends every connection to the service through a HTTP
+	// reverse proxy
+	if opts.Mode == ModeRoute {
+		for i := range ports {
+			if !routablePort(&ports[i].ServicePort) {
+				return nil, fmt.Errorf("port %s (%d) isn't known to serve HTTP or gRPC, set its appProtocol "+
+					"or prefix its name with http- or grpc- to route it", ports[i].Name, ports[i].Port)
+			}
+		}
 	}
 
-	if len(s.Spec.Selector) == 0 {
-		return nil, fmt.Errorf("headless services are not supported")
+	// generate fresh credentials for this session, these are shared by
+	// every pod created during it. Nothing has to be undone if it fails.
+	creds, err := ssh.GenerateCredentials()
+	if err != nil {
+		return nil, err
 	}
 
-	objects, err := c.getServiceControllers(ctx, namespace, serviceName)
+	log.Info("acquiring expose lock")
+	lease, err := c.acquireLease(ctx, namespace, serviceName, opts.Force)
 	if err != nil {
-		// service either had no controllers, or we failed to get them. Either way
-		// it's likely not the end of the world
-		c.log.WithError(err).Debug("failed to get controllers")
+		return nil, err
 	}
 
-	log := c.log.WithField("service", fmt.Sprintf("%s/%s", namespace, serviceName))
+	var objects []disabledObject
+	if opts.Create {
+		log.Info("service was created, there are no workloads to scale down")
+	} else if opts.Mode == ModeReplace {
+		var err error
+		objects, err = c.getServiceControllers(ctx, namespace, serviceName)
+		if err != nil {
+			// service either had no controllers, or we failed to get them. Either way
+			// it's likely not the end of the world
+			c.log.WithError(err).Debug("failed to get controllers")
+		}
+
+		for i := range objects {
+			log.Infof("found workload %s", objects[i].GetKey())
+		}
+		if len(objects) == 0 {
+			log.Warn("found no workloads to scale down, existing replicas will keep receiving traffic")
+		}
+	} else {
+		// intercepting and routing don't touch the existing controllers,
+		// so there's no need to find them
+		log.Infof("exposing service in %s mode, existing replicas will keep receiving traffic", opts.Mode)
+	}
 
 	return &ServiceForward{
-		c:           c,
-		log:         log,
-		ServiceName: serviceName,
-		Namespace:   namespace,
-		Selector:    s.Spec.Selector,
-		Ports:       ports,
-		objects:     objects,
+		c:            c,
+		log:          log,
+		ServiceName:  serviceName,
+		Namespace:    namespace,
+		Selector:     s.Spec.Selector,
+		Ports:        ports,
+		Mode:         opts.Mode,
+		Rules:        opts.Rules,
+		PodOptions:   podOptions,
+		objects:      objects,
+		lease:        lease,
+		creds:        creds,
+		ready:        make(chan struct{}),
+		status:       tunnelStatus{state: TunnelStateStarting},
+		created:      opts.Create,
+		DrainTimeout: drainTimeout,
 	}, nil
 }
@@ -0,0 +1,116 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains functions for exposing services that don't exist in the cluster yet.
+package expose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// CreatedServiceLabel marks a service created by expose, its value is the
+// name of the service. It's also used as the service's selector.
+const CreatedServiceLabel = "localizer.jaredallard.github.com/created-service"
+
+// createdServiceSelector returns the selector, and labels, of a service
+// created by createService
+func createdServiceSelector(serviceName string) map[string]string {
+	return map[string]string{CreatedServiceLabel: serviceName}
+}
+
+// checkServiceMissing returns an error if a service that's about to be
+// created already exists
+func (c *Client) checkServiceMissing(ctx context.Context, namespace, serviceName string) error {
+	_, err := c.k.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err == nil {
+		return fmt.Errorf("service %s/%s already exists, expose it without --create", namespace, serviceName)
+	} else if !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to get service")
+	}
+	return nil
+}
+
+// createService creates a service that selects the expose pod, for
+// exposing a local service that doesn't exist in the cluster yet
+func (c *Client) createService(ctx context.Context, namespace, serviceName string,
+	ports []kube.ResolvedServicePort) (*corev1.Service, error) {
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("at least one port is required to create a service")
+	}
+
+	servicePorts := make([]corev1.ServicePort, len(ports))
+	for i := range ports {
+		servicePorts[i] = ports[i].ServicePort
+	}
+
+	labels := createdServiceSelector(serviceName)
+	s, err := c.k.CoreV1().Services(namespace).Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      serviceName,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    servicePorts,
+		},
+	}, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("service %s/%s already exists, expose it without --create", namespace, serviceName)
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to create service")
+	}
+
+	c.log.Infof("created service %s/%s", namespace, serviceName)
+	return s, nil
+}
+
+// deleteCreatedService deletes a service created by createService. Services
+// that weren't created by expose are left alone.
+func (c *Client) deleteCreatedService(ctx context.Context, namespace, serviceName string) error {
+	s, err := c.k.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if s.Labels[CreatedServiceLabel] != serviceName {
+		c.log.Warnf("service %s/%s wasn't created by localizer, not deleting it", namespace, serviceName)
+		return nil
+	}
+
+	c.log.Infof("deleting service %s/%s", namespace, serviceName)
+	err = c.k.CoreV1().Services(namespace).Delete(ctx, serviceName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &s.UID},
+	})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// NewServicePorts returns the ports of a service created by expose, each
+// port is forwarded to the same port locally
+func NewServicePorts(ports []uint16) []kube.ResolvedServicePort {
+	resolved := make([]kube.ResolvedServicePort, len(ports))
+	for i, port := range ports {
+		resolved[i] = kube.ResolvedServicePort{
+			ServicePort: corev1.ServicePort{
+				Name:       fmt.Sprintf("tcp-%d", port),
+				Protocol:   corev1.ProtocolTCP,
+				Port:       int32(port),
+				TargetPort: intstr.FromInt32(int32(port)),
+			},
+			MappedPort: uint(port),
+		}
+	}
+	return resolved
+}
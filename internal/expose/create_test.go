@@ -0,0 +1,79 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package expose.
+package expose
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestClient_createService(t *testing.T) {
+	existing := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing"}}
+	k := fake.NewClientset(existing)
+	c := &Client{k: k, log: logrus.New()}
+	ctx := context.Background()
+
+	s, err := c.createService(ctx, "default", "app", NewServicePorts([]uint16{8080}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{CreatedServiceLabel: "app"}
+	if diff := cmp.Diff(expected, s.Spec.Selector); diff != "" {
+		t.Errorf("createService() selector mismatch (-want +got):\n%s", diff)
+	}
+	if s.Spec.Ports[0].Port != 8080 || s.Spec.Ports[0].TargetPort.IntValue() != 8080 {
+		t.Errorf("createService() ports = %v, want 8080", s.Spec.Ports)
+	}
+
+	if _, err := c.createService(ctx, "default", "existing", NewServicePorts([]uint16{8080})); err == nil {
+		t.Error("createService() of an existing service didn't fail")
+	}
+
+	// services that weren't created by localizer are left alone
+	for _, name := range []string{"app", "existing"} {
+		if err := c.deleteCreatedService(ctx, "default", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := k.CoreV1().Services("default").Get(ctx, "app", metav1.GetOptions{}); !kerrors.IsNotFound(err) {
+		t.Errorf("created service wasn't deleted, got %v", err)
+	}
+	if _, err := k.CoreV1().Services("default").Get(ctx, "existing", metav1.GetOptions{}); err != nil {
+		t.Errorf("existing service was deleted: %v", err)
+	}
+}
+
+func TestServiceForward_StartCreateSessionFails(t *testing.T) {
+	k := fake.NewClientset()
+	k.PrependReactor("create", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("etcd is down")
+	})
+	c := &Client{k: k, log: logrus.New(), owner: "alice@laptop"}
+	ctx := context.Background()
+
+	p, err := c.Expose(ctx, NewServicePorts([]uint16{8080}), "default", "app", &ExposeOpts{Mode: ModeReplace, Create: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Start(ctx); err == nil {
+		t.Fatal("Start() didn't fail when the session couldn't be created")
+	}
+
+	// the service is only created once the session records it
+	if _, err := k.CoreV1().Services("default").Get(ctx, "app", metav1.GetOptions{}); !kerrors.IsNotFound(err) {
+		t.Errorf("service was created without a session, got %v", err)
+	}
+}
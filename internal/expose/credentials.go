@@ -0,0 +1,112 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains helpers for delivering SSH credentials to expose pods.
+package expose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getoutreach/localizer/internal/agent"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// authorizedKeysSecretKey is the key of the client's public key in the
+	// credentials secret
+	authorizedKeysSecretKey = agent.AuthorizedKeysFile
+
+	// hostKeySecretKey is the key of the host's private key in the
+	// credentials secret
+	hostKeySecretKey = agent.HostKeyFile
+
+	// hostPublicKeySecretKey is the key of the host's public key in the
+	// credentials secret
+	hostPublicKeySecretKey = agent.HostKeyFile + ".pub"
+
+	// hostKeysDir is where the SSH server reads its host keys from
+	hostKeysDir = "/config/ssh_host_keys"
+)
+
+// createCredentialsSecret creates a secret containing the session's SSH
+// credentials for the expose pod to use
+func (p *ServiceForward) createCredentialsSecret(ctx context.Context) (*corev1.Secret, error) {
+	hostKey, err := p.creds.HostPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    p.Namespace,
+			GenerateName: fmt.Sprintf("localizer-%s-", p.ServiceName),
+			Labels: map[string]string{
+				ExposedPodLabel: "true",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			authorizedKeysSecretKey: []byte(p.creds.AuthorizedKey() + "\n"),
+			hostKeySecretKey:        hostKey,
+			hostPublicKeySecretKey:  []byte(p.creds.HostPublicKey() + "\n"),
+		},
+	}
+
+	secret, err = p.c.k.CoreV1().Secrets(p.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create credentials secret")
+	}
+	return secret, nil
+}
+
+// setCredentialsSecretOwner makes the expose pod the owner of the
+// credentials secret, so that it's garbage collected with the pod even if
+// localizer doesn't get a chance to clean it up.
+func (p *ServiceForward) setCredentialsSecretOwner(ctx context.Context, secret *corev1.Secret, po *corev1.Pod) error {
+	secret.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       po.Name,
+		UID:        po.UID,
+	}}
+
+	_, err := p.c.k.CoreV1().Secrets(p.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return errors.Wrap(err, "failed to set owner of credentials secret")
+}
+
+// deleteCredentialsSecret deletes the credentials secret, this is only
+// required if the pod that owns it was never created.
+func (p *ServiceForward) deleteCredentialsSecret(secret *corev1.Secret) {
+	err := p.c.k.CoreV1().Secrets(p.Namespace).Delete(context.Background(), secret.Name, metav1.DeleteOptions{})
+	if err != nil {
+		p.log.WithError(err).Warn("failed to delete credentials secret")
+	}
+}
+
+// credentialsVolume returns the volume, and mounts, used to provide the
+// host keys to the SSH server. The keys are mounted individually so the
+// server is still able to write any other host keys it generates.
+func credentialsVolume(secret *corev1.Secret) (corev1.Volume, []corev1.VolumeMount) {
+	volume := corev1.Volume{
+		Name: "host-keys",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secret.Name,
+				// sshd refuses to use host keys that are readable by other
+				// users. The kubelet makes the files group readable for the
+				// pod's fsGroup, which is the group sshd runs as.
+				DefaultMode: ptr.To(int32(0o440)),
+			},
+		},
+	}
+
+	mounts := []corev1.VolumeMount{
+		{Name: volume.Name, MountPath: hostKeysDir + "/" + hostKeySecretKey, SubPath: hostKeySecretKey, ReadOnly: true},
+		{Name: volume.Name, MountPath: hostKeysDir + "/" + hostPublicKeySecretKey, SubPath: hostPublicKeySecretKey, ReadOnly: true},
+	}
+
+	return volume, mounts
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the strategies used to stop workloads from receiving a service's traffic.
+package expose
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// DisabledNodeLabel is the node selector added to DaemonSets to stop them
+// from running on any node while they're exposed
+const DisabledNodeLabel = "localizer.jaredallard.github.com/disabled"
+
+// disableStrategy is how an object is stopped from serving a service's
+// traffic
+type disableStrategy string
+
+const (
+	// strategyScale scales the object to zero through the scale
+	// subresource, this works for any controller implementing /scale,
+	// e.g. Deployments, StatefulSets, ReplicaSets and Argo Rollouts.
+	strategyScale disableStrategy = "scale"
+
+	// strategyNodeSelector adds a node selector that matches no node to
+	// the object's pod template, this is used for DaemonSets.
+	strategyNodeSelector disableStrategy = "nodeSelector"
+
+	// strategyLabels removes the service's selector labels from a pod
+	// that has no controller, so that it's no longer an endpoint.
+	strategyLabels disableStrategy = "labels"
+)
+
+// disabledObject is an object that was disabled while a service is
+// exposed, it's stored on the expose pod so that it can be restored if
+// localizer dies.
+type disabledObject struct {
+	Strategy  disableStrategy `json:"strategy"`
+	Group     string          `json:"group"`
+	Version   string          `json:"version"`
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+
+	// Resource is the resource type (REST) for this object
+	// for example, a Deployment would be deployments
+	Resource string `json:"resource"`
+
+	// Replicas is the original scale at the time of scale down
+	// for this controller, only used by strategyScale
+	Replicas int `json:"replicas"`
+
+	// Autoscalers are the autoscalers targeting this object, they're
+	// paused while it's scaled down. Only used by strategyScale.
+	Autoscalers []pausedAutoscaler `json:"autoscalers,omitempty"`
+
+	// Labels are the labels removed from a pod, only used by
+	// strategyLabels
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Object is only set by older versions of localizer, which only
+	// supported scaling apps/v1 controllers.
+	Object *metav1.PartialObjectMetadata `json:"object,omitempty"`
+}
+
+// normalize converts objects stored by older versions of localizer into
+// the current format
+func (o *disabledObject) normalize() {
+	if o.Strategy != "" {
+		return
+	}
+
+	o.Strategy = strategyScale
+	o.Group, o.Version = "apps", "v1"
+	if o.Object != nil {
+		o.Namespace, o.Name = o.Object.Namespace, o.Object.Name
+		o.Object = nil
+	}
+}
+
+// GroupVersionResource returns the resource of the object
+func (o *disabledObject) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: o.Group, Version: o.Version, Resource: o.Resource}
+}
+
+// GetKey() returns a unique, predictable key for the given
+// disabledObject capable of being used for caching
+func (o *disabledObject) GetKey() string {
+	return fmt.Sprintf("%s/%s/%s", o.GroupVersionResource().GroupResource(), o.Namespace, o.Name)
+}
+
+// newDisabledObject determines how to disable the owner of a service's
+// pods. An error is returned if the owner can't be disabled.
+func (c *Client) newDisabledObject(ctx context.Context, svc *corev1.Service, owner *kube.Owner) (*disabledObject, error) {
+	o := &disabledObject{
+		Group:     owner.Resource.Group,
+		Version:   owner.Resource.Version,
+		Resource:  owner.Resource.Resource,
+		Namespace: owner.Namespace,
+		Name:      owner.Name,
+	}
+
+	switch {
+	case owner.IsPod():
+		podLabels := owner.Object.GetLabels()
+		if podLabels[ExposedPodLabel] == "true" {
+			return nil, fmt.Errorf("pod is a localizer pod")
+		}
+
+		o.Strategy = strategyLabels
+		o.Labels = make(map[string]string)
+		for k := range svc.Spec.Selector {
+			o.Labels[k] = podLabels[k]
+		}
+	case owner.Resource.Group == "apps" && owner.Kind == "DaemonSet":
+		o.Strategy = strategyNodeSelector
+	default:
+		scale, err := c.scales.Scales(o.Namespace).Get(ctx, owner.Resource.GroupResource(), o.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s doesn't support the scale subresource", o.GetKey())
+		}
+
+		o.Strategy = strategyScale
+		o.Replicas = int(scale.Spec.Replicas)
+
+		o.Autoscalers, err = c.findAutoscalers(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+// disableObject stops an object from serving traffic for a service
+func (c *Client) disableObject(ctx context.Context, o *disabledObject) error {
+	log := c.log.WithField("object", o.GetKey())
+
+	switch o.Strategy {
+	case strategyScale:
+		// pause autoscalers first, otherwise they'd scale it back up
+		for i := range o.Autoscalers {
+			if err := c.pauseAutoscaler(ctx, o.Namespace, &o.Autoscalers[i]); err != nil {
+				return errors.Wrapf(err, "failed to pause autoscaler %s", o.Autoscalers[i].Name)
+			}
+		}
+
+		log.Infof("scaling from %d -> 0", o.Replicas)
+		return c.scaleObject(ctx, o, 0)
+	case strategyNodeSelector:
+		log.Info("adding node selector to stop pods from being scheduled")
+		return c.patchObject(ctx, o, map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"nodeSelector": map[string]interface{}{DisabledNodeLabel: "true"},
+			}}},
+		})
+	case strategyLabels:
+		log.Info("removing service labels from pod")
+		labels := make(map[string]interface{})
+		for k := range o.Labels {
+			labels[k] = nil
+		}
+		return c.patchObject(ctx, o, map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": labels},
+		})
+	}
+
+	return fmt.Errorf("unknown strategy %q", o.Strategy)
+}
+
+// restoreObject undoes disableObject
+func (c *Client) restoreObject(ctx context.Context, o *disabledObject) error {
+	log := c.log.WithField("object", o.GetKey())
+
+	var err error
+	switch o.Strategy {
+	case strategyScale:
+		log.Infof("scaling from 0 -> %d", o.Replicas)
+		errs := make([]error, 0)
+		if err := c.scaleObject(ctx, o, o.Replicas); kerrors.IsNotFound(err) {
+			log.Warn("object no longer exists, not restoring it")
+		} else if err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to restore replicas"))
+		}
+
+		// autoscalers are resumed after the original scale is restored, and
+		// even if it couldn't be, so that they're never left paused
+		for i := range o.Autoscalers {
+			if err := c.resumeAutoscaler(ctx, o.Namespace, &o.Autoscalers[i]); err != nil {
+				errs = append(errs, errors.Wrapf(err, "failed to resume autoscaler %s", o.Autoscalers[i].Name))
+			}
+		}
+		return stderrors.Join(errs...)
+	case strategyNodeSelector:
+		log.Info("removing node selector")
+		err = c.patchObject(ctx, o, map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"nodeSelector": map[string]interface{}{DisabledNodeLabel: nil},
+			}}},
+		})
+	case strategyLabels:
+		log.Info("restoring service labels on pod")
+		err = c.patchObject(ctx, o, map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": o.Labels},
+		})
+	default:
+		return fmt.Errorf("unknown strategy %q", o.Strategy)
+	}
+
+	// nothing to restore if the object was deleted in the meantime
+	if kerrors.IsNotFound(err) {
+		log.Warn("object no longer exists, not restoring it")
+		return nil
+	}
+	return err
+}
+
+// scaleObject sets the replicas of an object through its scale
+// subresource. The scale is updated with the resourceVersion it was read
+// at, and retried if the object was modified in the meantime.
+func (c *Client) scaleObject(ctx context.Context, o *disabledObject, replicas int) error {
+	gr := o.GroupVersionResource().GroupResource()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scale, err := c.scales.Scales(o.Namespace).Get(ctx, gr, o.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		//nolint:gosec // Why: replicas is never negative or larger than an int32
+		scale.Spec.Replicas = int32(replicas)
+		_, err = c.scales.Scales(o.Namespace).Update(ctx, gr, scale, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// patchObject applies a JSON merge patch to an object
+func (c *Client) patchObject(ctx context.Context, o *disabledObject, patch map[string]interface{}) error {
+	payload, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal patch body")
+	}
+
+	_, err = c.dyn.Resource(o.GroupVersionResource()).Namespace(o.Namespace).Patch(ctx, o.Name, types.MergePatchType, payload,
+		metav1.PatchOptions{})
+	return err
+}
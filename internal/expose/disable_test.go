@@ -0,0 +1,183 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package expose.
+package expose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	scalefake "k8s.io/client-go/scale/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+func TestDisabledObject_normalize(t *testing.T) {
+	// written by versions of localizer that only supported apps/v1
+	legacy := `[{"object":{"metadata":{"name":"app","namespace":"default"}},"replicas":3,"resource":"deployments"}]`
+
+	var objects []disabledObject
+	if err := json.Unmarshal([]byte(legacy), &objects); err != nil {
+		t.Fatal(err)
+	}
+	objects[0].normalize()
+
+	expected := []disabledObject{{
+		Strategy:  strategyScale,
+		Group:     "apps",
+		Version:   "v1",
+		Namespace: "default",
+		Name:      "app",
+		Resource:  "deployments",
+		Replicas:  3,
+	}}
+	if diff := cmp.Diff(expected, objects); diff != "" {
+		t.Errorf("normalize() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_disableObject_Labels(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "bare",
+		Labels:    map[string]string{"app": "bare", "team": "platform"},
+	}}
+
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), mustToUnstructured(t, pod))
+	c := &Client{log: logrus.New(), dyn: dyn}
+
+	o := &disabledObject{
+		Strategy:  strategyLabels,
+		Version:   "v1",
+		Resource:  "pods",
+		Namespace: "default",
+		Name:      "bare",
+		Labels:    map[string]string{"app": "bare"},
+	}
+
+	getLabels := func() map[string]string {
+		u, err := dyn.Resource(o.GroupVersionResource()).Namespace("default").Get(context.Background(), "bare", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u.GetLabels()
+	}
+
+	if err := c.disableObject(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"team": "platform"}, getLabels()); diff != "" {
+		t.Errorf("disableObject() labels mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := c.restoreObject(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(pod.Labels, getLabels()); diff != "" {
+		t.Errorf("restoreObject() labels mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// mustToUnstructured converts a typed object into a runtime.Object the
+// dynamic fake client accepts
+func mustToUnstructured(t *testing.T, obj runtime.Object) runtime.Object {
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+
+	u := &unstructured.Unstructured{}
+	u.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestClient_scaleObject_RetriesOnConflict(t *testing.T) {
+	scales := &scalefake.FakeScaleClient{}
+
+	resourceVersion := 0
+	scales.AddReactor("get", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+		resourceVersion++
+		return true, &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", ResourceVersion: strconv.Itoa(resourceVersion)},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: 3},
+		}, nil
+	})
+
+	var updates []string
+	scales.AddReactor("update", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		scale := action.(clienttesting.UpdateAction).GetObject().(*autoscalingv1.Scale) //nolint:errcheck // Why: test
+		updates = append(updates, fmt.Sprintf("%s=%d", scale.ResourceVersion, scale.Spec.Replicas))
+
+		// simulate something else modifying the deployment between the
+		// first read and write
+		if scale.ResourceVersion == "1" {
+			return true, nil, kerrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "app", nil)
+		}
+		return true, scale, nil
+	})
+
+	c := &Client{log: logrus.New(), scales: scales}
+	o := &disabledObject{Strategy: strategyScale, Group: "apps", Version: "v1", Resource: "deployments", Namespace: "default", Name: "app"}
+	if err := c.scaleObject(context.Background(), o, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"1=0", "2=0"}, updates); diff != "" {
+		t.Errorf("scaleObject() updates mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_restoreObject_ResumesAutoscalersWhenScaleFails(t *testing.T) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: pausedTargetPrefix + "app"},
+			MinReplicas:    ptr.To(int32(1)),
+			MaxReplicas:    1,
+		},
+	}
+	k := fake.NewClientset(hpa)
+
+	scales := &scalefake.FakeScaleClient{}
+	scales.AddReactor("get", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kerrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "app", nil)
+	})
+
+	c := &Client{k: k, log: logrus.New(), scales: scales}
+	o := &disabledObject{
+		Strategy: strategyScale, Group: "apps", Version: "v1", Resource: "deployments", Namespace: "default", Name: "app",
+		Replicas:    3,
+		Autoscalers: []pausedAutoscaler{{Kind: autoscalerHPA, Name: "app", Target: "app", MinReplicas: ptr.To(int32(2)), MaxReplicas: 10}},
+	}
+	if err := c.restoreObject(context.Background(), o); !kerrors.IsForbidden(err) {
+		t.Fatalf("restoreObject() error = %v, expected the scale's error", err)
+	}
+
+	got, err := k.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.Background(), "app", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.ScaleTargetRef.Name != "app" || got.Spec.MaxReplicas != 10 {
+		t.Errorf("restoreObject() didn't resume the autoscaler, got %+v", got.Spec)
+	}
+}
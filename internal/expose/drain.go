@@ -0,0 +1,220 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains functions for gracefully stopping an expose.
+package expose
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// DefaultDrainTimeout is how long a stopping expose waits for the
+	// connections going through its tunnel to finish
+	DefaultDrainTimeout = 30 * time.Second
+
+	// restoreTimeout is how long a stopped expose has to restore the
+	// workloads it disabled, and wait for them to become ready, once it
+	// has drained
+	restoreTimeout = 2 * time.Minute
+
+	// drainPollInterval is how often draining connections and restored
+	// workloads are checked
+	drainPollInterval = time.Second
+)
+
+// restoreBackoff is used to retry restoring an object, e.g. when the API
+// server is briefly unavailable
+var restoreBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+// cleanupContext returns a context that outlives ctx by timeout, bounding
+// how long cleaning up after a stopped expose can take
+func cleanupContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	cleanupCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-cleanupCtx.Done():
+			return
+		}
+
+		select {
+		case <-time.After(timeout):
+		case <-cleanupCtx.Done():
+		}
+		cancel()
+	}()
+	return cleanupCtx, cancel
+}
+
+// stopTraffic stops the service from sending new traffic to the expose
+// pod. In route mode the service is pointed back at the original
+// workload, otherwise the service's labels are removed from the pod.
+func (p *ServiceForward) stopTraffic(ctx context.Context) error {
+	if p.Mode == ModeRoute {
+		p.log.Info("restoring service selector")
+		if err := p.c.setServiceSelector(ctx, p.Namespace, p.ServiceName, p.Selector); err != nil {
+			return errors.Wrap(err, "failed to restore service selector")
+		}
+		return errors.Wrap(p.c.deleteOriginService(ctx, p.Namespace, p.ServiceName), "failed to delete origin service")
+	}
+
+	p.status.mu.Lock()
+	pod := p.status.pod
+	p.status.mu.Unlock()
+	if pod == "" {
+		return nil
+	}
+
+	labels := make(map[string]interface{}, len(p.Selector))
+	for k := range p.Selector {
+		labels[k] = nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": labels},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal patch body")
+	}
+
+	p.log.Infof("removing expose pod %s from the service", pod)
+	_, err = p.c.k.CoreV1().Pods(p.Namespace).Patch(ctx, pod, types.MergePatchType, patch, metav1.PatchOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, "failed to remove service labels from expose pod")
+}
+
+// drain stops new traffic from being sent to the expose pod, and waits for
+// the connections already going through the tunnel to finish, up to
+// DrainTimeout
+func (p *ServiceForward) drain(ctx context.Context) {
+	if err := p.stopTraffic(ctx); err != nil {
+		p.log.WithError(err).Warn("failed to stop traffic to the expose pod")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.DrainTimeout)
+	defer cancel()
+
+	t := time.NewTicker(drainPollInterval)
+	defer t.Stop()
+
+	last := 0
+	for {
+		active := p.status.active()
+		if active == 0 {
+			if last != 0 {
+				p.log.Info("all connections finished")
+			}
+			return
+		}
+
+		if active != last {
+			p.log.Infof("waiting for %d connection(s) to finish", active)
+			last = active
+		}
+
+		select {
+		case <-ctx.Done():
+			p.log.Warnf("connections didn't finish within %s, closing %d connection(s)", p.DrainTimeout, active)
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// restoreObjects restores every object disabled by the expose, retrying
+// failures until ctx is done. The last failure is returned. If waitReady
+// is set, it then waits for them to become ready.
+func (p *ServiceForward) restoreObjects(ctx context.Context, waitReady bool) error {
+	var lastErr error
+	restored := make([]*disabledObject, 0, len(p.objects))
+	for i := range p.objects {
+		o := &p.objects[i]
+		err := retry.OnError(restoreBackoff, func(error) bool { return ctx.Err() == nil }, func() error {
+			err := p.c.restoreObject(ctx, o)
+			if err != nil {
+				p.log.WithError(err).WithField("object", o.GetKey()).Warn("failed to restore object")
+			}
+			return err
+		})
+		if err != nil {
+			lastErr = errors.Wrapf(err, "failed to restore %s", o.GetKey())
+			continue
+		}
+		restored = append(restored, o)
+	}
+
+	if waitReady && len(restored) != 0 {
+
+		for _, o := range restored {
+			p.log.Infof("waiting for %s to become ready", o.GetKey())
+			if err := p.c.waitForObject(ctx, o); err != nil {
+				p.log.WithError(err).WithField("object", o.GetKey()).Warn("object didn't become ready, continuing")
+			}
+		}
+	}
+
+	return lastErr
+}
+
+// waitForObject waits until a restored object is ready to serve traffic
+func (c *Client) waitForObject(ctx context.Context, o *disabledObject) error {
+	t := time.NewTicker(drainPollInterval)
+	defer t.Stop()
+
+	for {
+		u, err := c.dyn.Resource(o.GroupVersionResource()).Namespace(o.Namespace).Get(ctx, o.Name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return nil
+		} else if err != nil && ctx.Err() == nil {
+			c.log.WithError(err).WithField("object", o.GetKey()).Debug("failed to get object")
+		} else if err == nil && objectReady(u, o) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// objectReady returns true if a restored object's pods are ready, based
+// on how it was disabled
+func objectReady(u *unstructured.Unstructured, o *disabledObject) bool {
+	// the status doesn't reflect the restored spec yet
+	observed, found, err := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	if err == nil && found && observed < u.GetGeneration() {
+		return false
+	}
+
+	switch o.Strategy {
+	case strategyScale:
+		ready, _, _ := unstructured.NestedInt64(u.Object, "status", "readyReplicas") //nolint:errcheck // Why: missing is zero
+		return ready >= int64(o.Replicas)
+	case strategyNodeSelector:
+		desired, _, _ := unstructured.NestedInt64(u.Object, "status", "desiredNumberScheduled") //nolint:errcheck // Why: missing is zero
+		ready, _, _ := unstructured.NestedInt64(u.Object, "status", "numberReady")              //nolint:errcheck // Why: missing is zero
+		return ready >= desired
+	case strategyLabels:
+		// the pod kept running, it only got its labels back
+		return true
+	}
+	return true
+}
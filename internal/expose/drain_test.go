@@ -0,0 +1,143 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package expose.
+package expose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	scalefake "k8s.io/client-go/scale/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestObjectReady(t *testing.T) {
+	object := func(generation int64, status map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+		u.SetGeneration(generation)
+		return u
+	}
+
+	tests := []struct {
+		name     string
+		obj      *unstructured.Unstructured
+		strategy disableStrategy
+		want     bool
+	}{
+		{
+			name:     "scaled up",
+			obj:      object(2, map[string]interface{}{"observedGeneration": int64(2), "readyReplicas": int64(3)}),
+			strategy: strategyScale,
+			want:     true,
+		},
+		{
+			name:     "pods not ready",
+			obj:      object(2, map[string]interface{}{"observedGeneration": int64(2), "readyReplicas": int64(1)}),
+			strategy: strategyScale,
+		},
+		{
+			name:     "status not updated yet",
+			obj:      object(3, map[string]interface{}{"observedGeneration": int64(2), "readyReplicas": int64(3)}),
+			strategy: strategyScale,
+		},
+		{
+			name:     "daemonset ready",
+			obj:      object(1, map[string]interface{}{"desiredNumberScheduled": int64(2), "numberReady": int64(2)}),
+			strategy: strategyNodeSelector,
+			want:     true,
+		},
+		{
+			name:     "daemonset not ready",
+			obj:      object(1, map[string]interface{}{"desiredNumberScheduled": int64(2), "numberReady": int64(1)}),
+			strategy: strategyNodeSelector,
+		},
+		{
+			name:     "bare pod",
+			obj:      object(1, nil),
+			strategy: strategyLabels,
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := objectReady(tt.obj, &disabledObject{Strategy: tt.strategy, Replicas: 3}); got != tt.want {
+				t.Errorf("objectReady() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceForward_stopTraffic(t *testing.T) {
+	k := fake.NewClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "localizer-app-abcde",
+		Labels:    map[string]string{"app": "app", ExposedPodLabel: "true"},
+	}})
+	p := &ServiceForward{
+		c:         &Client{k: k, log: logrus.New()},
+		log:       logrus.New(),
+		Namespace: "default",
+		Selector:  map[string]string{"app": "app"},
+	}
+	p.status.setPod("localizer-app-abcde")
+
+	if err := p.stopTraffic(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	po, err := k.CoreV1().Pods("default").Get(context.Background(), "localizer-app-abcde", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{ExposedPodLabel: "true"}, po.Labels); diff != "" {
+		t.Errorf("stopTraffic() labels mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServiceForward_restoreObjects_Retries(t *testing.T) {
+	backoff := restoreBackoff
+	restoreBackoff.Duration = time.Millisecond
+	defer func() { restoreBackoff = backoff }()
+
+	scales := &scalefake.FakeScaleClient{}
+	scales.AddReactor("get", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &autoscalingv1.Scale{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}, nil
+	})
+
+	// the API server is unavailable for the first couple of attempts
+	var updates []int32
+	scales.AddReactor("update", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		scale := action.(clienttesting.UpdateAction).GetObject().(*autoscalingv1.Scale) //nolint:errcheck // Why: test
+		updates = append(updates, scale.Spec.Replicas)
+		if len(updates) < 3 {
+			return true, nil, kerrors.NewServiceUnavailable("apiserver is restarting")
+		}
+		return true, scale, nil
+	})
+
+	p := &ServiceForward{
+		c:   &Client{log: logrus.New(), scales: scales},
+		log: logrus.New(),
+		objects: []disabledObject{{
+			Strategy: strategyScale, Group: "apps", Version: "v1", Resource: "deployments",
+			Namespace: "default", Name: "app", Replicas: 2,
+		}},
+	}
+	if err := p.restoreObjects(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]int32{2, 2, 2}, updates); diff != "" {
+		t.Errorf("restoreObjects() updates mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the lease used to lock a service while it's exposed.
+package expose
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// ErrServiceLocked is returned when a service is already exposed by
+// another developer
+var ErrServiceLocked = errors.New("service is already exposed")
+
+// leaseName returns the name of the lease locking a service
+func leaseName(serviceName string) string {
+	return "localizer-expose-" + serviceName
+}
+
+// leaseExpired returns true if the holder of a lease stopped renewing it
+func leaseExpired(l *coordinationv1.Lease, now time.Time) bool {
+	if l.Spec.RenewTime == nil || l.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return now.After(l.Spec.RenewTime.Add(time.Duration(*l.Spec.LeaseDurationSeconds) * time.Second))
+}
+
+// leaseHolder returns the holder of a lease
+func leaseHolder(l *coordinationv1.Lease) string {
+	return ptr.Deref(l.Spec.HolderIdentity, "")
+}
+
+// acquireLease locks a service for the current developer. If the service
+// is locked by someone else, ErrServiceLocked is returned unless force is
+// set, in which case the lease is taken over. Leases that expired, or were
+// held by a previous daemon of the current developer, are always taken
+// over.
+//
+// Once acquired, any sessions left behind for the service are recovered,
+// so that the workloads are back to their original state before they're
+// recorded again.
+func (c *Client) acquireLease(ctx context.Context, namespace, serviceName string, force bool) (*coordinationv1.Lease, error) {
+	now := metav1.NewMicroTime(time.Now())
+	spec := coordinationv1.LeaseSpec{
+		HolderIdentity:       ptr.To(c.owner),
+		LeaseDurationSeconds: ptr.To(int32(SessionTTL / time.Second)),
+		AcquireTime:          &now,
+		RenewTime:            &now,
+	}
+
+	client := c.k.CoordinationV1().Leases(namespace)
+	l, err := client.Get(ctx, leaseName(serviceName), metav1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		l, err = client.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      leaseName(serviceName),
+				Labels:    map[string]string{SessionLabel: "true"},
+			},
+			Spec: spec,
+		}, metav1.CreateOptions{})
+		if kerrors.IsAlreadyExists(err) {
+			return nil, errors.Wrapf(ErrServiceLocked, "%s/%s was exposed by someone else at the same time", namespace, serviceName)
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to create lease")
+		}
+	case err != nil:
+		return nil, errors.Wrap(err, "failed to get lease")
+	default:
+		holder := leaseHolder(l)
+		if holder != c.owner && !leaseExpired(l, now.Time) {
+			if !force {
+				return nil, errors.Wrapf(ErrServiceLocked, "%s/%s is exposed by %s since %s, use --force to take over",
+					namespace, serviceName, holder, l.Spec.AcquireTime.Format(time.RFC3339))
+			}
+			c.log.WithField("holder", holder).Warnf("taking over expose of %s/%s", namespace, serviceName)
+		}
+
+		// the update fails if someone else modified the lease since it
+		// was read, e.g. because they took it over too
+		l.Spec = spec
+		l, err = client.Update(ctx, l, metav1.UpdateOptions{})
+		if kerrors.IsConflict(err) {
+			return nil, errors.Wrapf(ErrServiceLocked, "%s/%s was taken over by someone else at the same time", namespace, serviceName)
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to take over lease")
+		}
+	}
+
+	if err := c.recoverServiceSessions(ctx, namespace, serviceName); err != nil {
+		c.releaseLease(context.Background(), l)
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// renewLease renews a lease, returning false if it was taken over by
+// someone else
+func (c *Client) renewLease(ctx context.Context, l *coordinationv1.Lease) (*coordinationv1.Lease, bool, error) {
+	client := c.k.CoordinationV1().Leases(l.Namespace)
+	current, err := client.Get(ctx, l.Name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return l, true, err
+	}
+
+	if current.UID != l.UID || leaseHolder(current) != c.owner {
+		return nil, false, nil
+	}
+
+	current.Spec.RenewTime = ptr.To(metav1.NewMicroTime(time.Now()))
+	current, err = client.Update(ctx, current, metav1.UpdateOptions{})
+	if err != nil {
+		return l, true, err
+	}
+	return current, true, nil
+}
+
+// leaseLoop renews a lease until the context is canceled, calling lost if
+// it's taken over by someone else. The latest version of the lease is
+// returned.
+func (c *Client) leaseLoop(ctx context.Context, l *coordinationv1.Lease, lost func()) *coordinationv1.Lease {
+	t := time.NewTicker(sessionRenewInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return l
+		case <-t.C:
+			renewed, held, err := c.renewLease(ctx, l)
+			if !held {
+				lost()
+				return l
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					c.log.WithError(err).WithField("lease", l.Namespace+"/"+l.Name).Warn("failed to renew expose lease")
+				}
+				continue
+			}
+			l = renewed
+		}
+	}
+}
+
+// releaseLease unlocks a service, if the lease is still held
+func (c *Client) releaseLease(ctx context.Context, l *coordinationv1.Lease) {
+	err := c.k.CoordinationV1().Leases(l.Namespace).Delete(ctx, l.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &l.UID, ResourceVersion: &l.ResourceVersion},
+	})
+	if err != nil && !kerrors.IsNotFound(err) && !kerrors.IsConflict(err) {
+		c.log.WithError(err).WithField("lease", l.Namespace+"/"+l.Name).Warn("failed to release expose lease")
+	}
+}
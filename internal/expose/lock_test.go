@@ -0,0 +1,81 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package expose.
+package expose
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClient_acquireLease(t *testing.T) {
+	ctx := context.Background()
+	k := fake.NewClientset()
+	alice := &Client{k: k, log: logrus.New(), owner: "alice@laptop"}
+	bob := &Client{k: k, log: logrus.New(), owner: "bob@laptop"}
+
+	lease, err := alice.acquireLease(ctx, "default", "app", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// record a session, as if alice's expose had started
+	cm, err := sessionConfigMap(&Session{Name: "localizer-session-app-abcde", Namespace: "default", Service: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.CoreV1().ConfigMaps("default").Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = bob.acquireLease(ctx, "default", "app", false)
+	if !errors.Is(err, ErrServiceLocked) || !strings.Contains(err.Error(), "alice@laptop") {
+		t.Fatalf("acquireLease() error = %v, expected it to be locked by alice", err)
+	}
+
+	if _, err := bob.acquireLease(ctx, "default", "app", true); err != nil {
+		t.Fatalf("acquireLease(force) error = %v", err)
+	}
+
+	// taking over recovers alice's session
+	if sessions, err := bob.ListSessions(ctx); err != nil || len(sessions) != 0 {
+		t.Errorf("ListSessions() = %v, %v, expected the previous session to be recovered", sessions, err)
+	}
+
+	// alice notices that the expose was taken over
+	if _, held, err := alice.renewLease(ctx, lease); held || err != nil {
+		t.Errorf("renewLease() held = %v, err = %v, expected the lease to be lost", held, err)
+	}
+}
+
+func TestLeaseExpired(t *testing.T) {
+	ctx := context.Background()
+	k := fake.NewClientset()
+	alice := &Client{k: k, log: logrus.New(), owner: "alice@laptop"}
+	bob := &Client{k: k, log: logrus.New(), owner: "bob@laptop"}
+
+	lease, err := alice.acquireLease(ctx, "default", "app", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaseExpired(lease, time.Now()) {
+		t.Error("leaseExpired() = true for a new lease")
+	}
+
+	// alice's daemon died and stopped renewing the lease
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now().Add(-2 * SessionTTL)}
+	if _, err := k.CoordinationV1().Leases("default").Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bob.acquireLease(ctx, "default", "app", false); err != nil {
+		t.Errorf("acquireLease() error = %v, expected expired lease to be taken over", err)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the configurable parts of expose pods.
+package expose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	oapp "github.com/getoutreach/gobox/pkg/app"
+	"github.com/getoutreach/localizer/internal/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+// TunnelImage is the flavor of image that runs the tunnel server in an
+// expose pod
+type TunnelImage string
+
+const (
+	// TunnelImageAgent uses the localizer agent, which speaks a
+	// multiplexed protocol instead of SSH, see internal/agent. This is
+	// the default.
+	TunnelImageAgent TunnelImage = "agent"
+
+	// TunnelImageOpenSSH uses linuxserver/openssh-server, which is
+	// configured at startup through a docker mod.
+	TunnelImageOpenSSH TunnelImage = "openssh"
+
+	// TunnelImageMinimal uses a small purpose-built OpenSSH image that
+	// runs as a non-root user with a read-only root filesystem, see
+	// deployments/localizer-tunnel.
+	TunnelImageMinimal TunnelImage = "minimal"
+
+	// TunnelImageRepository is the image repository of the minimal tunnel
+	// image, which is tagged with the version of localizer
+	TunnelImageRepository = "ghcr.io/getoutreach/localizer-tunnel"
+
+	// openSSHImage is the image used for TunnelImageOpenSSH
+	openSSHImage = "linuxserver/openssh-server"
+
+	// tunnelPort is the port the tunnel server listens on
+	tunnelPort = 2222
+
+	// keysDir is where the credentials secret is mounted when using
+	// TunnelImageMinimal or TunnelImageAgent
+	keysDir = "/etc/localizer/keys"
+)
+
+// ParseTunnelImage parses a tunnel image flavor, an empty string is
+// TunnelImageAgent
+func ParseTunnelImage(s string) (TunnelImage, error) {
+	switch TunnelImage(s) {
+	case "", TunnelImageAgent:
+		return TunnelImageAgent, nil
+	case TunnelImageOpenSSH:
+		return TunnelImageOpenSSH, nil
+	case TunnelImageMinimal:
+		return TunnelImageMinimal, nil
+	}
+	return "", fmt.Errorf("unknown tunnel image %q, expected one of: %s, %s, %s", s,
+		TunnelImageAgent, TunnelImageOpenSSH, TunnelImageMinimal)
+}
+
+// UsesSSH returns true if the tunnel image runs an SSH server
+func (t TunnelImage) UsesSSH() bool {
+	return t != TunnelImageAgent
+}
+
+// PodOptions configures the pod created by expose
+type PodOptions struct {
+	// TunnelImage is the flavor of image running the tunnel server
+	TunnelImage TunnelImage
+
+	// Image overrides the default image of the TunnelImage, e.g. to use
+	// a mirror in an air-gapped cluster
+	Image string
+
+	// AgentImage overrides the image of the localizer agent, which is used
+	// by ModeRoute
+	AgentImage string
+
+	ImagePullSecrets   []string
+	Resources          corev1.ResourceRequirements
+	NodeSelector       map[string]string
+	Tolerations        []corev1.Toleration
+	ServiceAccountName string
+
+	// Labels and Annotations are added to the pod, they can't override
+	// the ones used by localizer
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// DefaultPodOptions returns the options used when nothing has been
+// configured
+func DefaultPodOptions() *PodOptions {
+	return &PodOptions{
+		TunnelImage: TunnelImageAgent,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("100Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("100Mi"),
+			},
+		},
+	}
+}
+
+// Merge returns a copy of o with every field that's set in override
+// replaced. Maps, including resources, are merged key by key.
+func (o *PodOptions) Merge(override *PodOptions) *PodOptions {
+	merged := *o
+	if override == nil {
+		return &merged
+	}
+
+	if override.TunnelImage != "" {
+		merged.TunnelImage = override.TunnelImage
+	}
+	if override.Image != "" {
+		merged.Image = override.Image
+	}
+	if override.AgentImage != "" {
+		merged.AgentImage = override.AgentImage
+	}
+	if len(override.ImagePullSecrets) != 0 {
+		merged.ImagePullSecrets = override.ImagePullSecrets
+	}
+	if len(override.Tolerations) != 0 {
+		merged.Tolerations = override.Tolerations
+	}
+	if override.ServiceAccountName != "" {
+		merged.ServiceAccountName = override.ServiceAccountName
+	}
+
+	merged.Resources = corev1.ResourceRequirements{
+		Requests: mergeMaps(o.Resources.Requests, override.Resources.Requests),
+		Limits:   mergeMaps(o.Resources.Limits, override.Resources.Limits),
+	}
+	merged.NodeSelector = mergeMaps(o.NodeSelector, override.NodeSelector)
+	merged.Labels = mergeMaps(o.Labels, override.Labels)
+	merged.Annotations = mergeMaps(o.Annotations, override.Annotations)
+
+	return &merged
+}
+
+// mergeMaps returns a new map containing a and b, with b taking
+// precedence. If both are empty, nil is returned.
+func mergeMaps[K comparable, V any](a, b map[K]V) map[K]V {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	m := make(map[K]V, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+	for k, v := range b {
+		m[k] = v
+	}
+	return m
+}
+
+// image returns the image to use for the tunnel container
+func (o *PodOptions) image() string {
+	if o.Image != "" {
+		return o.Image
+	}
+
+	switch o.TunnelImage {
+	case TunnelImageMinimal:
+		return ImageForVersion(TunnelImageRepository)
+	case TunnelImageOpenSSH:
+		return openSSHImage
+	}
+	return o.agentImage()
+}
+
+// agentImage returns the image to use for the localizer agent
+func (o *PodOptions) agentImage() string {
+	if o.AgentImage != "" {
+		return o.AgentImage
+	}
+	return AgentImage()
+}
+
+// ImageForVersion returns repo tagged with this version of localizer,
+// development builds use the latest image.
+func ImageForVersion(repo string) string {
+	version := oapp.Info().Version
+	if !strings.HasPrefix(version, "v") || strings.Contains(version, "-") {
+		version = "latest"
+	}
+	return repo + ":" + version
+}
+
+// apply applies the pod level options to a pod. Labels and annotations
+// already set on the pod aren't overridden.
+func (o *PodOptions) apply(po *corev1.Pod) {
+	for k, v := range o.Labels {
+		if _, ok := po.Labels[k]; !ok {
+			po.Labels[k] = v
+		}
+	}
+	for k, v := range o.Annotations {
+		if _, ok := po.Annotations[k]; !ok {
+			po.Annotations[k] = v
+		}
+	}
+
+	for _, name := range o.ImagePullSecrets {
+		po.Spec.ImagePullSecrets = append(po.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
+	}
+	po.Spec.NodeSelector = o.NodeSelector
+	po.Spec.Tolerations = o.Tolerations
+	po.Spec.ServiceAccountName = o.ServiceAccountName
+}
+
+// tunnelContainer returns the container running the tunnel server, and
+// the volumes it requires, for the configured tunnel image
+func (o *PodOptions) tunnelContainer(secret *corev1.Secret, ports []corev1.ContainerPort) (corev1.Container, []corev1.Volume) {
+	container := corev1.Container{
+		Name:            "default",
+		Image:           o.image(),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Ports:           ports,
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{
+					Port: intstr.FromInt(tunnelPort),
+				},
+			},
+		},
+		Resources: o.Resources,
+	}
+
+	if o.TunnelImage == TunnelImageAgent {
+		container.Args = []string{"tunnel", "--listen", fmt.Sprintf(":%d", tunnelPort), "--keys-dir", keysDir}
+	}
+
+	if o.TunnelImage != TunnelImageOpenSSH {
+		// Everything the image needs is in the credentials secret, see
+		// deployments/localizer-tunnel/sshd_config and cmd/localizer-agent.
+		container.SecurityContext = &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			ReadOnlyRootFilesystem:   ptr.To(true),
+			RunAsNonRoot:             ptr.To(true),
+			RunAsUser:                ptr.To(int64(PodUID)),
+			RunAsGroup:               ptr.To(int64(PodGID)),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		}
+		container.VolumeMounts = []corev1.VolumeMount{
+			{Name: "keys", MountPath: keysDir, ReadOnly: true},
+		}
+
+		return container, []corev1.Volume{{
+			Name: "keys",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secret.Name,
+					// see credentialsVolume
+					DefaultMode: ptr.To(int32(0o440)),
+				},
+			},
+		}}
+	}
+
+	hostKeysVolume, hostKeysMounts := credentialsVolume(secret)
+	container.Env = []corev1.EnvVar{
+		{Name: "PUID", Value: strconv.Itoa(PodUID)},
+		{Name: "PGID", Value: strconv.Itoa(PodGID)},
+		{Name: "PASSWORD_ACCESS", Value: "false"},
+		{Name: "USER_NAME", Value: ssh.Username},
+		{Name: "PUBLIC_KEY", ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+				Key:                  authorizedKeysSecretKey,
+			},
+		}},
+		{Name: "DOCKER_MODS", Value: "linuxserver/mods:openssh-server-ssh-tunnel"},
+	}
+	container.SecurityContext = &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities: &corev1.Capabilities{
+			Add:  []corev1.Capability{"CHOWN", "SETGID", "SETUID"},
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+	container.VolumeMounts = append([]corev1.VolumeMount{
+		{Name: "app", MountPath: "/app"},
+		{Name: "config", MountPath: "/config"},
+		{Name: "defaults", MountPath: "/defaults"},
+	}, hostKeysMounts...)
+
+	return container, []corev1.Volume{
+		{Name: "app", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		{Name: "config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		{Name: "defaults", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		hostKeysVolume,
+	}
+}
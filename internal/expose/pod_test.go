@@ -0,0 +1,47 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package expose.
+package expose
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPodOptions_Merge(t *testing.T) {
+	defaults := DefaultPodOptions()
+	defaults.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+
+	merged := defaults.Merge(&PodOptions{
+		TunnelImage:  TunnelImageMinimal,
+		Image:        "registry.internal/localizer-tunnel:v1",
+		NodeSelector: map[string]string{"pool": "dev"},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+	})
+
+	expected := &PodOptions{
+		TunnelImage:  TunnelImageMinimal,
+		Image:        "registry.internal/localizer-tunnel:v1",
+		NodeSelector: map[string]string{"kubernetes.io/os": "linux", "pool": "dev"},
+		Resources: corev1.ResourceRequirements{
+			Requests: defaults.Resources.Requests,
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, merged); diff != "" {
+		t.Errorf("unexpected merged options (-want +got):\n%s", diff)
+	}
+
+	// the defaults must not be modified
+	if defaults.Resources.Limits.Memory().String() != "100Mi" {
+		t.Errorf("expected defaults to be unchanged, got memory limit %s", defaults.Resources.Limits.Memory())
+	}
+}
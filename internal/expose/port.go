@@ -20,18 +20,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/getoutreach/localizer/internal/router"
 	"github.com/getoutreach/localizer/internal/ssh"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/portforward"
 	"k8s.io/utils/ptr"
 )
@@ -52,9 +53,9 @@ var (
 	// connection. This results in the transport protocol dying as well.
 	ErrUnderlyingTransportDied = errors.New("underlying transport died")
 
-	// ErrUnderlyingTransportProtocolDied is triggered when the ssh tunnel loses connection,
+	// ErrUnderlyingTransportProtocolDied is triggered when the tunnel loses connection,
 	// this can be due to the ssh connection being destroyed or the port-forward being killed
-	ErrUnderlyingTransportProtocolDied = errors.New("underlying transport protocol (ssh) died")
+	ErrUnderlyingTransportProtocolDied = errors.New("underlying transport protocol died")
 
 	// ErrNotInitialized is used to start the initialization
 	// process. It is not an error, despite its name.
@@ -65,6 +66,87 @@ var (
 	ErrUnderlyingTransportPodDestroyed = errors.New("underlying transport pod died")
 )
 
+// Mode is how an exposed service receives traffic
+type Mode int
+
+const (
+	// ModeReplace scales down the workloads powering the service so that
+	// all of its traffic is sent to the local machine.
+	ModeReplace Mode = iota
+
+	// ModeIntercept adds the expose pod to the service alongside the
+	// existing replicas, so that it receives a share of the traffic
+	// without scaling anything down. This is useful for clusters that
+	// are shared with other developers.
+	ModeIntercept
+
+	// ModeRoute runs a HTTP router in the expose pod that sends requests
+	// matching a set of header rules to the local machine, and everything
+	// else to the original workload. The service's selector is pointed at
+	// the expose pod for the duration of the expose.
+	ModeRoute
+)
+
+// String returns the name of the mode
+func (m Mode) String() string {
+	switch m {
+	case ModeReplace:
+		return "replace"
+	case ModeIntercept:
+		return "intercept"
+	case ModeRoute:
+		return "route"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (m Mode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (m *Mode) UnmarshalText(b []byte) error {
+	for _, mode := range []Mode{ModeReplace, ModeIntercept, ModeRoute} {
+		if mode.String() == string(b) {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown mode %q", string(b))
+}
+
+// ExposeOpts are options for exposing a service
+type ExposeOpts struct {
+	// Mode is how the exposed service receives traffic, defaults to
+	// ModeReplace.
+	Mode Mode
+
+	// Rules are the header rules a request must match to be sent to the
+	// local machine in ModeRoute.
+	Rules []router.Rule
+
+	// PodOptions configures the expose pod, defaults to
+	// DefaultPodOptions.
+	PodOptions *PodOptions
+
+	// Force takes over the service if it's already exposed by someone
+	// else, restoring it to its original state first.
+	Force bool
+
+	// Log receives the progress of the expose, defaults to the client's
+	// logger.
+	Log logrus.FieldLogger
+
+	// Create creates the service with the given ports, instead of
+	// exposing an existing one. It's deleted when the expose stops.
+	Create bool
+
+	// DrainTimeout is how long to wait for connections to finish when
+	// the expose is stopped, defaults to DefaultDrainTimeout.
+	DrainTimeout time.Duration
+}
+
 type ServiceForward struct {
 	c   *Client
 	log logrus.FieldLogger
@@ -73,33 +155,46 @@ type ServiceForward struct {
 	Namespace   string
 	Selector    map[string]string
 	Ports       []kube.ResolvedServicePort
+	Mode        Mode
+	Rules       []router.Rule
 
-	// TODO(jaredallard): support replacing non associated pods?
-	objects []scaledObjectType
-}
+	// PodOptions configures the expose pod
+	PodOptions *PodOptions
 
-type scaledObjectType struct {
-	obj interface{}
+	// DrainTimeout is how long to wait for connections to finish when
+	// the expose is stopped
+	DrainTimeout time.Duration
 
-	*metav1.PartialObjectMetadata `json:"object"`
+	// creds are the SSH credentials for this expose session
+	creds *ssh.Credentials
 
-	// Replicas is the original scale at the time of scale down
-	// for this controller
-	Replicas int `json:"replicas"`
+	// objects are disabled while the service is exposed
+	objects []disabledObject
 
-	// Resource is the resource type (REST) for this controller
-	// for example, a Deployment would be deployments
-	Resource string `json:"resource"`
-}
+	// session is the record of this expose stored in the cluster
+	session *Session
+
+	// lease locks the service while it's exposed
+	lease *coordinationv1.Lease
+
+	// lost is set when the lease was taken over by someone else, who is
+	// now responsible for restoring the service
+	lost atomic.Bool
 
-// GetKey() returns a unique, predictable key for the given
-// scaledObjectType capable of being used for caching
-func (s *scaledObjectType) GetKey() string {
-	return s.GetSelfLink()
+	// ready is closed once the tunnel is first forwarding traffic
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	// status is reported by Status
+	status tunnelStatus
+
+	// created is set if the service was created by the expose
+	created bool
 }
 
 func (p *ServiceForward) createServerPortForward(ctx context.Context, po *corev1.Pod, localPort int) (*portforward.PortForwarder, error) {
-	return kube.CreatePortForward(ctx, p.c.k.CoreV1().RESTClient(), p.c.kconf, po, "0.0.0.0", []string{fmt.Sprintf("%d:2222", localPort)})
+	return kube.CreatePortForward(ctx, p.c.k.CoreV1().RESTClient(), p.c.kconf, po, "0.0.0.0",
+		[]string{fmt.Sprintf("%d:2222", localPort)}, p.c.transport)
 }
 
 func (p *ServiceForward) createServerPod(ctx context.Context) (func(), *corev1.Pod, error) { //nolint:funlen,lll // Why: there are no reusable parts to extract
@@ -123,95 +218,81 @@ func (p *ServiceForward) createServerPod(ctx context.Context) (func(), *corev1.P
 
 	// add a label for localizer pods
 	labels := map[string]string{
-		ExposedPodLabel: "true",
+		ExposedPodLabel:  "true",
+		SessionNameLabel: p.session.Name,
+	}
+	annotations := map[string]string{
+		ObjectsPodLabel: string(b),
+	}
+
+	if p.Mode == ModeRoute {
+		// The service's selector is pointed at this label instead, the
+		// router needs to be the only thing receiving traffic for it.
+		labels[RoutePodLabel] = p.ServiceName
+
+		selector, err := json.Marshal(p.Selector)
+		if err != nil {
+			return func() {}, nil, errors.Wrap(err, "failed to encode service selector")
+		}
+		annotations[SelectorPodLabel] = string(selector)
+
+		// the router listens on the service's ports instead
+		containerPorts = nil
+	} else {
+		for k, v := range p.Selector {
+			labels[k] = v
+		}
 	}
 
-	for k, v := range p.Selector {
-		labels[k] = v
+	secret, err := p.createCredentialsSecret(ctx)
+	if err != nil {
+		return func() {}, nil, err
 	}
+	container, volumes := p.PodOptions.tunnelContainer(secret, containerPorts)
 
 	podObject := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:    p.Namespace,
 			GenerateName: fmt.Sprintf("localizer-%s-", p.ServiceName),
 			Labels:       labels,
-			Annotations: map[string]string{
-				ObjectsPodLabel: string(b),
-			},
+			Annotations:  annotations,
 		},
 		Spec: corev1.PodSpec{
 			RestartPolicy: corev1.RestartPolicyOnFailure,
-			Containers: []corev1.Container{
-				{
-					Name:            "default",
-					Image:           "linuxserver/openssh-server",
-					ImagePullPolicy: corev1.PullIfNotPresent,
-					Ports:           containerPorts,
-					Env: []corev1.EnvVar{
-						{Name: "PUID", Value: strconv.Itoa(PodUID)},
-						{Name: "PGID", Value: strconv.Itoa(PodGID)},
-						{Name: "PASSWORD_ACCESS", Value: "true"},
-						{Name: "USER_PASSWORD", Value: "supersecretpassword"},
-						{Name: "USER_NAME", Value: "outreach"},
-						{Name: "DOCKER_MODS", Value: "linuxserver/mods:openssh-server-ssh-tunnel"},
-					},
-					ReadinessProbe: &corev1.Probe{
-						ProbeHandler: corev1.ProbeHandler{
-							TCPSocket: &corev1.TCPSocketAction{
-								Port: intstr.FromInt(2222),
-							},
-						},
-					},
-					Resources: corev1.ResourceRequirements{
-						Requests: corev1.ResourceList{
-							corev1.ResourceCPU:    resource.MustParse("100m"),
-							corev1.ResourceMemory: resource.MustParse("100Mi"),
-						},
-						Limits: corev1.ResourceList{
-							corev1.ResourceCPU:    resource.MustParse("100m"),
-							corev1.ResourceMemory: resource.MustParse("100Mi"),
-						},
-					},
-					SecurityContext: &corev1.SecurityContext{
-						AllowPrivilegeEscalation: ptr.To(false),
-						Capabilities: &corev1.Capabilities{
-							Add:  []corev1.Capability{"CHOWN", "SETGID", "SETUID"},
-							Drop: []corev1.Capability{"ALL"},
-						},
-					},
-					VolumeMounts: []corev1.VolumeMount{
-						{Name: "app", MountPath: "/app"},
-						{Name: "config", MountPath: "/config"},
-						{Name: "defaults", MountPath: "/defaults"},
-					},
-				},
-			},
+			Containers:    []corev1.Container{container},
 			SecurityContext: &corev1.PodSecurityContext{
 				SeccompProfile: &corev1.SeccompProfile{
 					Type: corev1.SeccompProfileTypeRuntimeDefault,
 				},
 				FSGroup: ptr.To(int64(PodGID)),
 			},
-			Volumes: []corev1.Volume{
-				{Name: "app", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
-				{Name: "config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
-				{Name: "defaults", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
-			},
+			Volumes: volumes,
 		},
 	}
+	p.PodOptions.apply(podObject)
+	if p.Mode == ModeRoute {
+		podObject.Spec.Containers = append(podObject.Spec.Containers, p.routerContainer())
+	}
 	p.log.Debug(spew.Sdump(podObject))
 
 	po, err := p.c.k.CoreV1().Pods(p.Namespace).Create(ctx, podObject, metav1.CreateOptions{})
 	if err != nil {
+		p.deleteCredentialsSecret(secret)
 		return func() {}, nil, errors.Wrap(err, "failed to create pod")
 	}
 
+	if err := p.setCredentialsSecretOwner(ctx, secret, po); err != nil {
+		// not fatal, we still delete it when cleaning up the pod
+		p.log.WithError(err).Warn("failed to set owner of credentials secret")
+	}
+
 	cleanupFn := func() {
 		p.log.Debug("cleaning up pod")
 		// cleanup the pod
 		if err := p.c.k.CoreV1().Pods(p.Namespace).Delete(context.Background(), po.Name, metav1.DeleteOptions{}); err != nil {
 			p.log.WithError(err).Warn("failed to delete pod")
 		}
+		p.deleteCredentialsSecret(secret)
 	}
 
 	p.log.Infof("created pod %s", po.ObjectMeta.Name)
@@ -287,39 +368,141 @@ func (p *ServiceForward) createTransport(ctx context.Context, po *corev1.Pod,
 	return localPort, fw, nil
 }
 
-// Start starts forwarding a service, this blocks
-func (p *ServiceForward) Start(ctx context.Context) error {
+// Ready returns a channel that's closed once the tunnel is forwarding
+// traffic for the first time
+func (p *ServiceForward) Ready() <-chan struct{} {
+	return p.ready
+}
+
+// Start starts forwarding a service, this blocks until ctx is canceled
+// and the service has been restored. Restoring is bounded by the drain
+// timeout plus restoreTimeout, if it fails an error is returned and the
+// session is kept so that it's recovered once it expires.
+func (p *ServiceForward) Start(ctx context.Context) (err error) { //nolint:funlen // Why: there are no reusable parts to extract
 	ports := make([]string, len(p.Ports))
 	for i, port := range p.Ports {
 		prt := int(port.TargetPort.IntVal)
-		ports[i] = fmt.Sprintf("%d:%d", port.MappedPort, prt)
+		ports[i] = fmt.Sprintf("%s:%d", localTarget(&port), prt)
 		p.log.Debugf("tunneling port %v", ports[i])
 	}
 
-	// scale down the other resources that powered this service
-	for _, o := range p.objects {
-		p.log.Infof("scaling %s from %d -> 0", o.GetKey(), o.Replicas)
-		if err := p.c.scaleObject(ctx, o, 0); err != nil {
-			return errors.Wrap(err, "failed to scale down object")
+	// ctx is canceled to stop the expose, but the tunnel, lease and
+	// session are kept alive on runCtx until the expose has drained. If
+	// someone else takes over the service both are canceled right away.
+	// Cleaning up is bounded by cleanupCtx.
+	cleanupCtx, cancelCleanup := cleanupContext(ctx, p.DrainTimeout+restoreTimeout)
+	defer cancelCleanup()
+	runCtx, cancelRun := context.WithCancel(cleanupCtx)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(runCtx, cancel)()
+
+	// the lease is released last, once everything has been restored
+	leaseDone := make(chan *coordinationv1.Lease, 1)
+	go func() {
+		leaseDone <- p.c.leaseLoop(runCtx, p.lease, func() {
+			p.log.Warn("expose was taken over by someone else, stopping")
+			p.lost.Store(true)
+			cancelRun()
+		})
+	}()
+	defer func() {
+		cancelRun()
+		if l := <-leaseDone; !p.lost.Load() {
+			p.c.releaseLease(context.Background(), l)
+		}
+	}()
+
+	// record the session before anything is modified, so that it can be
+	// undone if we die
+	session := &Session{
+		Namespace: p.Namespace,
+		Service:   p.ServiceName,
+		Mode:      p.Mode,
+		Ports:     ports,
+		Objects:   p.objects,
+		Created:   p.created,
+	}
+	if p.Mode == ModeRoute {
+		session.Selector = p.Selector
+	}
+	if err := p.c.createSession(ctx, session); err != nil {
+		return err
+	}
+	p.session = session
+	p.log.Infof("recorded expose session %s", session.Name)
+	go p.c.renewSessionLoop(runCtx, session)
+
+	// restore the resources that powered this service, this is safe to
+	// do for objects that failed to be disabled. When stopped normally
+	// they're restored before the pod is deleted, otherwise when Start
+	// returns.
+	objectsRestored := false
+	var restoreErr error
+	restore := func(wait bool) {
+		if !objectsRestored {
+			objectsRestored = true
+			restoreErr = p.restoreObjects(cleanupCtx, wait)
 		}
 	}
 	defer func() {
-		// scale back up the resources that powered this service
-		for _, o := range p.objects {
-			p.log.Infof("scaling %s from 0 -> %d", o.GetKey(), o.Replicas)
-			if err := p.c.scaleObject(context.Background(), o, o.Replicas); err != nil {
-				p.log.WithError(err).Warn("failed to scale back up object")
+		// the new holder already restored everything
+		if p.lost.Load() {
+			return
+		}
+
+		restore(false)
+		if p.created && restoreErr == nil {
+			if derr := p.c.deleteCreatedService(cleanupCtx, p.Namespace, p.ServiceName); derr != nil {
+				restoreErr = errors.Wrap(derr, "failed to delete created service")
+			}
+		}
+
+		// keep the session around if anything failed, so that it's
+		// retried once it expires
+		if restoreErr != nil {
+			p.log.WithError(restoreErr).Error("failed to restore service, it will be restored once its session expires")
+			if err == nil {
+				err = restoreErr
 			}
+			return
+		}
+		if derr := p.c.deleteSession(cleanupCtx, session); derr != nil {
+			p.log.WithError(derr).Warn("failed to delete expose session")
 		}
 	}()
 
+	// the service is created once the session records it, so that it's
+	// deleted even if we die
+	if p.created {
+		if _, err := p.c.createService(ctx, p.Namespace, p.ServiceName, p.Ports); err != nil {
+			return err
+		}
+	}
+
+	if p.Mode == ModeRoute {
+		ports = p.routeTunnelPortStrings()
+		if err := p.createOriginService(ctx); err != nil {
+			return err
+		}
+	}
+
+	// disable the other resources that powered this service
+	for i := range p.objects {
+		if err := p.c.disableObject(ctx, &p.objects[i]); err != nil {
+			return errors.Wrapf(err, "failed to disable %s", p.objects[i].GetKey())
+		}
+	}
+
 	lastErr := ErrNotInitialized
 	localPort := 0
 	cleanupFn := func() {}
 
 	var po *corev1.Pod
 	var fw *portforward.PortForwarder
+	loopDone := make(chan struct{})
 	go func() {
+		defer close(loopDone)
 		for {
 			select {
 			case <-ctx.Done():
@@ -329,6 +512,7 @@ func (p *ServiceForward) Start(ctx context.Context) error {
 					p.log.Debug("creating tunnel connection")
 				} else {
 					p.log.WithError(lastErr).Errorf("connection died, recreating tunnel connection")
+					p.status.reconnecting(lastErr)
 				}
 
 				if !errors.Is(lastErr, ErrNotInitialized) {
@@ -347,15 +531,29 @@ func (p *ServiceForward) Start(ctx context.Context) error {
 				cleanupFn()
 
 				var err error
+				p.status.setState(TunnelStateWaitingForPod)
 				cleanupFn, po, err = p.createServerPod(ctx)
 				if err != nil {
-					p.log.WithError(err).Debug("failed to create pod")
+					p.log.WithError(err).Warn("failed to create pod")
 					lastErr = ErrUnderlyingTransportPodDestroyed
 					continue
 				}
+				p.status.setPod(po.Name)
+
+				// only point the service at the router once it's ready
+				if p.Mode == ModeRoute {
+					err = p.c.setServiceSelector(ctx, p.Namespace, p.ServiceName, map[string]string{RoutePodLabel: p.ServiceName})
+					if err != nil {
+						p.log.WithError(err).Warn("failed to route service to expose pod")
+						lastErr = err
+						continue
+					}
+				}
 
+				p.log.Info("creating tunnel to pod")
+				p.status.setState(TunnelStateConnecting)
 				errorChan := make(chan error)
-				localPort, fw, err = p.createTransport(ctx, po, 0, errorChan)
+				localPort, fw, err = p.createTransport(runCtx, po, 0, errorChan)
 				if err != nil {
 					if fw != nil {
 						fw.Close()
@@ -366,9 +564,29 @@ func (p *ServiceForward) Start(ctx context.Context) error {
 					continue
 				}
 
-				cli := ssh.NewReverseTunnelClient(p.log, "127.0.0.1", localPort, ports)
+				cli, err := p.newTunnel(localPort, ports)
+				if err != nil {
+					fw.Close()
+					p.log.WithError(err).Error("failed to create tunnel")
+					lastErr = err
+					continue
+				}
+				p.status.setTunnel(cli)
 				go func() {
-					errorChan <- cli.Start(ctx, p.ServiceName)
+					errorChan <- cli.Start(runCtx, p.Namespace+"/"+p.ServiceName)
+				}()
+
+				tunnelCtx, cancelTunnel := context.WithCancel(ctx)
+				go func() {
+					select {
+					case <-tunnelCtx.Done():
+					case <-cli.Ready():
+						p.status.setState(TunnelStateConnected)
+						p.readyOnce.Do(func() {
+							p.log.Info("tunnel is up")
+							close(p.ready)
+						})
+					}
 				}()
 
 				// handle errors
@@ -376,10 +594,16 @@ func (p *ServiceForward) Start(ctx context.Context) error {
 				// clean up
 				select {
 				case <-ctx.Done():
+					// keep tunneling until the expose has drained
+					select {
+					case <-runCtx.Done():
+					case <-errorChan:
+					}
 				case err := <-errorChan:
 					p.log.WithError(err).Debug("transport died")
 					lastErr = err
 				}
+				cancelTunnel()
 
 				// cleanup the port-forward if the above died
 				if fw != nil {
@@ -392,6 +616,21 @@ func (p *ServiceForward) Start(ctx context.Context) error {
 	// wait for the context to finish
 	<-ctx.Done()
 
+	// Stop sending traffic to the pod and let the connections that are
+	// still going through the tunnel finish, then bring the original
+	// workload back before removing the pod so that no requests are
+	// dropped.
+	if !p.lost.Load() {
+		p.log.Info("stopping expose, draining connections")
+		p.drain(runCtx)
+	}
+	cancelRun()
+	<-loopDone
+
+	if !p.lost.Load() {
+		restore(true)
+	}
+
 	cleanupFn()
 	return nil
 }
@@ -0,0 +1,243 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the header based routing expose mode.
+package expose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// RoutePodLabel is set on expose pods, and origin services, created
+	// in ModeRoute. Its value is the name of the exposed service.
+	RoutePodLabel = "localizer.jaredallard.github.com/route"
+
+	// SelectorPodLabel is an annotation on expose pods created in
+	// ModeRoute containing the original selector of the exposed service
+	SelectorPodLabel = "localizer.jaredallard.github.com/selector"
+
+	// AgentImageRepository is the image repository of the localizer
+	// agent, which is tagged with the version of localizer
+	AgentImageRepository = "ghcr.io/getoutreach/localizer-agent"
+
+	// routeTunnelPortBase is the first port the reverse tunnel listens on
+	// inside of the expose pod in ModeRoute, the router listens on the
+	// service's target ports instead.
+	routeTunnelPortBase = 40000
+)
+
+// routableAppProtocols are the appProtocols of ports the router can
+// route, it doesn't terminate TLS
+var routableAppProtocols = []string{"http", "h2c", "grpc", "kubernetes.io/h2c", "kubernetes.io/ws"}
+
+// routablePortPrefixes are the prefixes of the names of ports the router
+// can route, used when a port has no appProtocol. This follows the
+// convention of service meshes, e.g. http-web.
+var routablePortPrefixes = []string{"http", "http2", "grpc", "h2c"}
+
+// routablePort returns true if port serves plain HTTP, or gRPC, which is
+// required to route it by headers
+func routablePort(port *corev1.ServicePort) bool {
+	if port.AppProtocol != nil {
+		return slices.Contains(routableAppProtocols, strings.ToLower(*port.AppProtocol))
+	}
+
+	for _, prefix := range routablePortPrefixes {
+		if port.Name == prefix || strings.HasPrefix(port.Name, prefix+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// AgentImage returns the localizer agent image matching this version of
+// localizer, development builds use the latest image.
+func AgentImage() string {
+	return ImageForVersion(AgentImageRepository)
+}
+
+// originServiceName returns the name of the service that points to the
+// original workload of a service exposed in ModeRoute
+func originServiceName(serviceName string) string {
+	name := "localizer-origin-" + serviceName
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+// routeTunnelPorts returns the port, inside of the expose pod, the
+// reverse tunnel listens on for each of the service's ports when in
+// ModeRoute. Ports used by the service are skipped.
+func (p *ServiceForward) routeTunnelPorts() []int32 {
+	used := make(map[int32]bool, len(p.Ports))
+	for _, port := range p.Ports {
+		used[port.TargetPort.IntVal] = true
+	}
+
+	ports := make([]int32, len(p.Ports))
+	next := int32(routeTunnelPortBase)
+	for i := range p.Ports {
+		for used[next] {
+			next++
+		}
+		ports[i] = next
+		next++
+	}
+	return ports
+}
+
+// routerContainer returns the container that runs the router in the
+// expose pod when in ModeRoute
+func (p *ServiceForward) routerContainer() corev1.Container {
+	tunnelPorts := p.routeTunnelPorts()
+
+	args := []string{"route", "--origin-host", fmt.Sprintf("%s.%s.svc", originServiceName(p.ServiceName), p.Namespace)}
+	containerPorts := make([]corev1.ContainerPort, len(p.Ports))
+	for i, port := range p.Ports {
+		args = append(args, "--port", fmt.Sprintf("%d:%d", port.TargetPort.IntVal, tunnelPorts[i]))
+		containerPorts[i] = corev1.ContainerPort{
+			ContainerPort: port.TargetPort.IntVal,
+			Name:          port.OriginalTargetPort,
+			Protocol:      corev1.ProtocolTCP,
+		}
+	}
+	for _, rule := range p.Rules {
+		args = append(args, "--header", rule.String())
+	}
+
+	return corev1.Container{
+		Name:            "router",
+		Image:           p.PodOptions.agentImage(),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Args:            args,
+		Ports:           containerPorts,
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			ReadOnlyRootFilesystem:   ptr.To(true),
+			RunAsNonRoot:             ptr.To(true),
+			RunAsUser:                ptr.To(int64(PodUID)),
+			RunAsGroup:               ptr.To(int64(PodGID)),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}
+}
+
+// createOriginService creates a service pointing at the original
+// workload of the exposed service, which the router sends non-matching
+// requests to. The ports of the service are the target ports of the
+// exposed service.
+func (p *ServiceForward) createOriginService(ctx context.Context) error {
+	ports := make([]corev1.ServicePort, len(p.Ports))
+	for i, port := range p.Ports {
+		ports[i] = corev1.ServicePort{
+			Name:       port.Name,
+			Protocol:   corev1.ProtocolTCP,
+			Port:       port.TargetPort.IntVal,
+			TargetPort: intstr.FromInt32(port.TargetPort.IntVal),
+		}
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      originServiceName(p.ServiceName),
+			Namespace: p.Namespace,
+			Labels: map[string]string{
+				ExposedPodLabel: "true",
+				RoutePodLabel:   p.ServiceName,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: p.Selector,
+			Ports:    ports,
+		},
+	}
+
+	_, err := p.c.k.CoreV1().Services(p.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		// left over from a previous session, update it in case the
+		// service has changed since
+		var existing *corev1.Service
+		existing, err = p.c.k.CoreV1().Services(p.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to get existing origin service")
+		}
+		existing.Spec.Selector = svc.Spec.Selector
+		existing.Spec.Ports = svc.Spec.Ports
+		_, err = p.c.k.CoreV1().Services(p.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to create origin service")
+	}
+
+	return nil
+}
+
+// deleteOriginService deletes the service created by createOriginService
+func (c *Client) deleteOriginService(ctx context.Context, namespace, serviceName string) error {
+	err := c.k.CoreV1().Services(namespace).Delete(ctx, originServiceName(serviceName), metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete origin service")
+	}
+	return nil
+}
+
+// setServiceSelector replaces the selector of a service
+func (c *Client) setServiceSelector(ctx context.Context, namespace, serviceName string, selector map[string]string) error {
+	payload, err := json.Marshal([]map[string]interface{}{{
+		"op":    "replace",
+		"path":  "/spec/selector",
+		"value": selector,
+	}})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal selector patch body")
+	}
+
+	_, err = c.k.CoreV1().Services(namespace).Patch(ctx, serviceName, types.JSONPatchType, payload, metav1.PatchOptions{})
+	return errors.Wrap(err, "failed to patch service selector")
+}
+
+// restoreRoutedService restores a service exposed in ModeRoute using
+// the annotations of its expose pod
+func (c *Client) restoreRoutedService(ctx context.Context, po *corev1.Pod) error {
+	serviceName := po.Labels[RoutePodLabel]
+
+	var selector map[string]string
+	if err := json.Unmarshal([]byte(po.Annotations[SelectorPodLabel]), &selector); err != nil {
+		return errors.Wrap(err, "failed to decode original service selector")
+	}
+
+	if err := c.setServiceSelector(ctx, po.Namespace, serviceName, selector); err != nil {
+		return err
+	}
+	return c.deleteOriginService(ctx, po.Namespace, serviceName)
+}
+
+// routeTunnelPortStrings returns the ports the reverse tunnel should
+// forward, in the format accepted by ssh.NewReverseTunnelClient
+func (p *ServiceForward) routeTunnelPortStrings() []string {
+	tunnelPorts := p.routeTunnelPorts()
+	ports := make([]string, len(p.Ports))
+	for i, port := range p.Ports {
+		ports[i] = localTarget(&port) + ":" + strconv.Itoa(int(tunnelPorts[i]))
+	}
+	return ports
+}
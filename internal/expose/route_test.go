@@ -0,0 +1,37 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package expose.
+package expose
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestRoutablePort(t *testing.T) {
+	tests := []struct {
+		name string
+		port corev1.ServicePort
+		want bool
+	}{
+		{"http name", corev1.ServicePort{Name: "http"}, true},
+		{"http prefixed name", corev1.ServicePort{Name: "http-web"}, true},
+		{"grpc prefixed name", corev1.ServicePort{Name: "grpc-api"}, true},
+		{"tcp name", corev1.ServicePort{Name: "tcp-8080"}, false},
+		{"name only starting with http", corev1.ServicePort{Name: "https"}, false},
+		{"unnamed", corev1.ServicePort{}, false},
+		{"grpc appProtocol", corev1.ServicePort{Name: "api", AppProtocol: ptr.To("grpc")}, true},
+		{"h2c appProtocol", corev1.ServicePort{Name: "api", AppProtocol: ptr.To("kubernetes.io/h2c")}, true},
+		{"appProtocol takes precedence", corev1.ServicePort{Name: "http", AppProtocol: ptr.To("https")}, false},
+		{"database appProtocol", corev1.ServicePort{Name: "db", AppProtocol: ptr.To("postgresql")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := routablePort(&tt.port); got != tt.want {
+				t.Errorf("routablePort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the expose session records stored in the cluster.
+package expose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// SessionLabel marks a ConfigMap as an expose session record
+	SessionLabel = "localizer.jaredallard.github.com/session"
+
+	// SessionNameLabel is the name of the session an expose pod belongs to
+	SessionNameLabel = "localizer.jaredallard.github.com/session-name"
+
+	// SessionTTL is how long a session is kept after it was last renewed,
+	// after which the daemon of its owner will garbage collect it
+	SessionTTL = 5 * time.Minute
+
+	// AbandonedSessionTTL is how long a session has to be expired, and its
+	// lease too, before the daemons of other developers garbage collect
+	// it. It's much longer than SessionTTL, since a daemon stops renewing
+	// its sessions while its machine is asleep.
+	AbandonedSessionTTL = 24 * time.Hour
+
+	// sessionRenewInterval is how often sessions are renewed
+	sessionRenewInterval = time.Minute
+
+	// sessionGCInterval is how often expired sessions are garbage
+	// collected
+	sessionGCInterval = 10 * time.Minute
+
+	// sessionDataKey is the key of the session in its ConfigMap
+	sessionDataKey = "session.json"
+)
+
+// Session is the record of an expose, stored in a ConfigMap in the
+// namespace of the exposed service. It contains everything needed to undo
+// the expose if the daemon that created it dies.
+type Session struct {
+	// Name is the name of the ConfigMap the session is stored in
+	Name string `json:"-"`
+
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+
+	// Owner is the developer that created the session, user@hostname
+	Owner string `json:"owner"`
+
+	Mode Mode `json:"mode"`
+
+	// Ports are the ports being exposed in the format local:remote
+	Ports []string `json:"ports"`
+
+	// Selector is the original selector of the service, only set in
+	// ModeRoute
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// Objects are the objects disabled by the session
+	Objects []disabledObject `json:"objects"`
+
+	// Created is set if the service was created by the session, and
+	// should be deleted with it
+	Created bool `json:"created,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Expired returns true if the session wasn't renewed within SessionTTL
+func (s *Session) Expired(now time.Time) bool {
+	return now.After(s.ExpiresAt)
+}
+
+// ObjectKeys returns the keys of the objects disabled by the session,
+// e.g. deployments.apps/default/app
+func (s *Session) ObjectKeys() []string {
+	keys := make([]string, len(s.Objects))
+	for i := range s.Objects {
+		keys[i] = s.Objects[i].GetKey()
+	}
+	return keys
+}
+
+// sessionOwner returns the identity of the developer running localizer.
+// The daemon usually runs through sudo, so the invoking user is preferred.
+func sessionOwner() string {
+	username := os.Getenv("SUDO_USER")
+	if username == "" {
+		if u, err := user.Current(); err == nil {
+			username = u.Username
+		}
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s@%s", username, hostname)
+}
+
+// sessionConfigMap encodes a session into its ConfigMap
+func sessionConfigMap(s *Session) (*corev1.ConfigMap, error) {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode session")
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      s.Name,
+			Labels:    map[string]string{SessionLabel: "true"},
+		},
+		Data: map[string]string{sessionDataKey: string(b)},
+	}, nil
+}
+
+// sessionFromConfigMap decodes a session from its ConfigMap
+func sessionFromConfigMap(cm *corev1.ConfigMap) (*Session, error) {
+	var s Session
+	if err := json.Unmarshal([]byte(cm.Data[sessionDataKey]), &s); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode session %s/%s", cm.Namespace, cm.Name)
+	}
+	s.Name = cm.Name
+	for i := range s.Objects {
+		s.Objects[i].normalize()
+	}
+	return &s, nil
+}
+
+// createSession stores a new session, setting its name
+func (c *Client) createSession(ctx context.Context, s *Session) error {
+	now := time.Now().UTC()
+	s.Owner = c.owner
+	s.CreatedAt = now
+	s.ExpiresAt = now.Add(SessionTTL)
+
+	cm, err := sessionConfigMap(s)
+	if err != nil {
+		return err
+	}
+	cm.GenerateName = fmt.Sprintf("localizer-session-%s-", s.Service)
+
+	cm, err = c.k.CoreV1().ConfigMaps(s.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to create session")
+	}
+	s.Name = cm.Name
+	return nil
+}
+
+// renewSession pushes back the expiry of a session
+func (c *Client) renewSession(ctx context.Context, s *Session) error {
+	s.ExpiresAt = time.Now().UTC().Add(SessionTTL)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := c.k.CoreV1().ConfigMaps(s.Namespace).Get(ctx, s.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		updated, err := sessionConfigMap(s)
+		if err != nil {
+			return err
+		}
+		cm.Data = updated.Data
+
+		_, err = c.k.CoreV1().ConfigMaps(s.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// renewSessionLoop renews a session until the context is canceled
+func (c *Client) renewSessionLoop(ctx context.Context, s *Session) {
+	t := time.NewTicker(sessionRenewInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := c.renewSession(ctx, s); err != nil && ctx.Err() == nil {
+				c.log.WithError(err).WithField("session", s.Name).Warn("failed to renew expose session")
+			}
+		}
+	}
+}
+
+// deleteSession removes a session record
+func (c *Client) deleteSession(ctx context.Context, s *Session) error {
+	err := c.k.CoreV1().ConfigMaps(s.Namespace).Delete(ctx, s.Name, metav1.DeleteOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// ListSessions returns the expose sessions of every developer across the
+// cluster, sorted by namespace and service
+func (c *Client) ListSessions(ctx context.Context) ([]Session, error) {
+	cms, err := c.k.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{LabelSelector: SessionLabel})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list sessions")
+	}
+
+	sessions := make([]Session, 0, len(cms.Items))
+	for i := range cms.Items {
+		s, err := sessionFromConfigMap(&cms.Items[i])
+		if err != nil {
+			c.log.WithError(err).Warn("ignoring invalid expose session")
+			continue
+		}
+		sessions = append(sessions, *s)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].Namespace != sessions[j].Namespace {
+			return sessions[i].Namespace < sessions[j].Namespace
+		}
+		return sessions[i].Service < sessions[j].Service
+	})
+	return sessions, nil
+}
+
+// recoverSession undoes an expose from its session record, and removes
+// the session. The session is kept if anything fails to be restored, so
+// that it can be retried.
+func (c *Client) recoverSession(ctx context.Context, s *Session) error {
+	log := c.log.WithField("session", s.Namespace+"/"+s.Name)
+	log.WithField("owner", s.Owner).Warn("recovering abandoned expose session")
+
+	var lastErr error
+	if s.Mode == ModeRoute && len(s.Selector) != 0 {
+		if err := c.setServiceSelector(ctx, s.Namespace, s.Service, s.Selector); err != nil {
+			lastErr = errors.Wrap(err, "failed to restore service selector")
+		} else if err := c.deleteOriginService(ctx, s.Namespace, s.Service); err != nil {
+			log.WithError(err).Warn("failed to delete origin service")
+		}
+	}
+
+	err := c.k.CoreV1().Pods(s.Namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: SessionNameLabel + "=" + s.Name,
+	})
+	if err != nil {
+		log.WithError(err).Warn("failed to remove localizer pods")
+	}
+
+	for i := range s.Objects {
+		if err := c.restoreObject(ctx, &s.Objects[i]); err != nil {
+			lastErr = errors.Wrapf(err, "failed to restore %s", s.Objects[i].GetKey())
+		}
+	}
+
+	if s.Created {
+		if err := c.deleteCreatedService(ctx, s.Namespace, s.Service); err != nil {
+			lastErr = errors.Wrap(err, "failed to delete created service")
+		}
+	}
+
+	if lastErr != nil {
+		return lastErr
+	}
+	return c.deleteSession(ctx, s)
+}
+
+// recoverServiceSessions recovers every session of a service, this must
+// only be called while holding its lease
+func (c *Client) recoverServiceSessions(ctx context.Context, namespace, serviceName string) error {
+	cms, err := c.k.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: SessionLabel})
+	if err != nil {
+		return errors.Wrap(err, "failed to list sessions")
+	}
+
+	for i := range cms.Items {
+		s, err := sessionFromConfigMap(&cms.Items[i])
+		if err != nil {
+			return err
+		}
+		if s.Service != serviceName {
+			continue
+		}
+
+		if err := c.recoverSession(ctx, s); err != nil {
+			return errors.Wrapf(err, "failed to recover previous session of %s/%s", namespace, serviceName)
+		}
+	}
+	return nil
+}
+
+// gcSessions recovers the expired sessions of the current developer, and
+// the sessions of other developers that were abandoned, see
+// sessionAbandoned. If own is set, every session of the current developer
+// is recovered, which is done on start up since they were abandoned by a
+// previous daemon.
+func (c *Client) gcSessions(ctx context.Context, own bool) {
+	sessions, err := c.ListSessions(ctx)
+	if err != nil {
+		c.log.WithError(err).Warn("failed to garbage collect expose sessions")
+		return
+	}
+
+	now := time.Now()
+	for i := range sessions {
+		s := &sessions[i]
+		if s.Owner == c.owner {
+			if !own && !s.Expired(now) {
+				continue
+			}
+		} else {
+			abandoned, err := c.sessionAbandoned(ctx, s, now)
+			if err != nil {
+				c.log.WithError(err).WithField("session", s.Namespace+"/"+s.Name).Warn("failed to check expose session")
+				continue
+			}
+			if !abandoned {
+				continue
+			}
+		}
+
+		if err := c.recoverSession(ctx, s); err != nil {
+			c.log.WithError(err).WithField("session", s.Namespace+"/"+s.Name).Warn("failed to recover expose session")
+		}
+	}
+}
+
+// sessionAbandoned returns true if the session of another developer
+// expired more than AbandonedSessionTTL ago, and the lease of its service
+// isn't held by anyone either
+func (c *Client) sessionAbandoned(ctx context.Context, s *Session, now time.Time) (bool, error) {
+	if !now.After(s.ExpiresAt.Add(AbandonedSessionTTL)) {
+		return false, nil
+	}
+
+	l, err := c.k.CoordinationV1().Leases(s.Namespace).Get(ctx, leaseName(s.Service), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, errors.Wrap(err, "failed to get lease")
+	}
+	return leaseExpired(l, now), nil
+}
+
+// gcSessionsLoop garbage collects expired sessions until the context is
+// canceled
+func (c *Client) gcSessionsLoop(ctx context.Context) {
+	t := time.NewTicker(sessionGCInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.gcSessions(ctx, false)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package expose.
+package expose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestClient_gcSessions(t *testing.T) {
+	now := time.Now().UTC()
+	session := func(name, owner string, expiresAt time.Time) *corev1.ConfigMap {
+		cm, err := sessionConfigMap(&Session{
+			Name:      name,
+			Namespace: "default",
+			Service:   name,
+			Owner:     owner,
+			Mode:      ModeIntercept,
+			Ports:     []string{"8080:8080"},
+			CreatedAt: now.Add(-time.Hour),
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cm
+	}
+
+	lease := func(service, holder string, renewed time.Time) *coordinationv1.Lease {
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: leaseName(service)},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(holder),
+				LeaseDurationSeconds: ptr.To(int32(SessionTTL / time.Second)),
+				RenewTime:            ptr.To(metav1.NewMicroTime(renewed)),
+			},
+		}
+	}
+
+	abandoned := now.Add(-AbandonedSessionTTL - time.Minute)
+	k := fake.NewClientset(
+		// bob's machine may just be asleep
+		session("expired", "bob@laptop", now.Add(-time.Minute)),
+		session("abandoned", "bob@laptop", abandoned),
+		session("abandoned-leased", "bob@laptop", abandoned),
+		lease("abandoned-leased", "bob@laptop", now),
+		session("abandoned-lease-expired", "bob@laptop", abandoned),
+		lease("abandoned-lease-expired", "bob@laptop", abandoned),
+		session("active", "bob@laptop", now.Add(time.Minute)),
+		session("own", "alice@laptop", now.Add(time.Minute)),
+		session("own-expired", "alice@laptop", now.Add(-time.Minute)),
+	)
+	c := &Client{k: k, log: logrus.New(), owner: "alice@laptop"}
+
+	remaining := func() []string {
+		sessions, err := c.ListSessions(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names := make([]string, len(sessions))
+		for i := range sessions {
+			names[i] = sessions[i].Name
+		}
+		return names
+	}
+
+	c.gcSessions(context.Background(), false)
+	if diff := cmp.Diff([]string{"abandoned-leased", "active", "expired", "own"}, remaining()); diff != "" {
+		t.Errorf("gcSessions(own=false) mismatch (-want +got):\n%s", diff)
+	}
+
+	c.gcSessions(context.Background(), true)
+	if diff := cmp.Diff([]string{"abandoned-leased", "active", "expired"}, remaining()); diff != "" {
+		t.Errorf("gcSessions(own=true) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSessionFromConfigMap(t *testing.T) {
+	s := &Session{
+		Name:      "localizer-session-app-abcde",
+		Namespace: "default",
+		Service:   "app",
+		Owner:     "alice@laptop",
+		Mode:      ModeRoute,
+		Ports:     []string{"8080:8080"},
+		Selector:  map[string]string{"app": "app"},
+		Objects: []disabledObject{{
+			Strategy: strategyScale, Group: "apps", Version: "v1", Resource: "deployments",
+			Namespace: "default", Name: "app", Replicas: 2,
+			Autoscalers: []pausedAutoscaler{{Kind: autoscalerHPA, Name: "app", Target: "app", MaxReplicas: 5}},
+		}},
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt: time.Date(2026, 1, 1, 0, 5, 0, 0, time.UTC),
+	}
+
+	cm, err := sessionConfigMap(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.Labels[SessionLabel] != "true" {
+		t.Errorf("sessionConfigMap() labels = %v", cm.Labels)
+	}
+
+	got, err := sessionFromConfigMap(cm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(s, got); diff != "" {
+		t.Errorf("sessionFromConfigMap() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"deployments.apps/default/app"}, got.ObjectKeys()); diff != "" {
+		t.Errorf("ObjectKeys() mismatch (-want +got):\n%s", diff)
+	}
+}
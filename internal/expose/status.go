@@ -0,0 +1,195 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the status reported for running exposes.
+package expose
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/getoutreach/localizer/internal/ssh"
+)
+
+// TunnelState is the state of an expose's tunnel
+type TunnelState string
+
+// This block contains the states of an expose's tunnel
+const (
+	// TunnelStateStarting is used while the expose is disabling the
+	// service's workloads
+	TunnelStateStarting TunnelState = "starting"
+
+	// TunnelStateWaitingForPod is used while the expose pod is being
+	// created and scheduled
+	TunnelStateWaitingForPod TunnelState = "waiting for pod"
+
+	// TunnelStateConnecting is used while the tunnel to the pod is being
+	// created
+	TunnelStateConnecting TunnelState = "connecting"
+
+	// TunnelStateConnected is used while traffic is being tunneled
+	TunnelStateConnected TunnelState = "connected"
+
+	// TunnelStateReconnecting is used when the tunnel died, until it's
+	// recreated
+	TunnelStateReconnecting TunnelState = "reconnecting"
+)
+
+// targetProbeTimeout is how long to wait when checking if a local target
+// is accepting connections
+const targetProbeTimeout = 500 * time.Millisecond
+
+// TargetStatus is the status of a local target traffic is tunneled to
+type TargetStatus struct {
+	// RemotePort is the port traffic is received on in the cluster
+	RemotePort uint
+
+	// Address is the local address traffic is sent to
+	Address string
+
+	// Reachable is true if the address accepted a connection
+	Reachable bool
+}
+
+// Status is the status of a running expose
+type Status struct {
+	Namespace string
+	Service   string
+	Mode      Mode
+
+	// Objects are the keys of the objects disabled by the expose
+	Objects []string
+
+	// Pod is the name of the current expose pod, if one was created
+	Pod string
+
+	State TunnelState
+
+	// Reconnects is the number of times the tunnel was recreated
+	Reconnects int
+
+	// LastError is the error that caused the last reconnect
+	LastError string
+
+	Targets []TargetStatus
+}
+
+// tunnelStatus is the mutable part of a ServiceForward's status
+type tunnelStatus struct {
+	mu         sync.Mutex
+	pod        string
+	state      TunnelState
+	reconnects int
+	lastErr    error
+
+	// tunnel is the current tunnel, if one was created
+	tunnel Tunnel
+}
+
+// setState updates the state of the tunnel
+func (t *tunnelStatus) setState(state TunnelState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state = state
+}
+
+// setTunnel updates the current tunnel
+func (t *tunnelStatus) setTunnel(tunnel Tunnel) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tunnel = tunnel
+}
+
+// active returns the number of connections going through the current
+// tunnel
+func (t *tunnelStatus) active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.tunnel == nil {
+		return 0
+	}
+	return t.tunnel.Active()
+}
+
+// setPod updates the current expose pod
+func (t *tunnelStatus) setPod(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pod = name
+}
+
+// reconnecting records that the tunnel died because of err
+func (t *tunnelStatus) reconnecting(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state = TunnelStateReconnecting
+	t.reconnects++
+	t.lastErr = err
+}
+
+// Status returns the status of the expose, checking if every local target
+// is reachable
+func (p *ServiceForward) Status() Status {
+	s := Status{
+		Namespace: p.Namespace,
+		Service:   p.ServiceName,
+		Mode:      p.Mode,
+		Objects:   make([]string, len(p.objects)),
+		Targets:   make([]TargetStatus, len(p.Ports)),
+	}
+	for i := range p.objects {
+		s.Objects[i] = p.objects[i].GetKey()
+	}
+
+	p.status.mu.Lock()
+	s.Pod = p.status.pod
+	s.State = p.status.state
+	s.Reconnects = p.status.reconnects
+	if p.status.lastErr != nil {
+		s.LastError = p.status.lastErr.Error()
+	}
+	p.status.mu.Unlock()
+
+	wg := sync.WaitGroup{}
+	for i := range p.Ports {
+		target, err := ssh.ParseTarget(localTarget(&p.Ports[i]))
+		s.Targets[i] = TargetStatus{
+			RemotePort: uint(p.Ports[i].TargetPort.IntValue()), //nolint:gosec // Why: ports are never negative
+			Address:    target.String(),
+		}
+		if err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(t *TargetStatus) {
+			defer wg.Done()
+			t.Reachable = probeTarget(target.Network, target.Address)
+		}(&s.Targets[i])
+	}
+	wg.Wait()
+
+	return s
+}
+
+// localTarget returns the local address traffic for port is sent to, in
+// the format accepted by ssh.ParseTarget
+func localTarget(port *kube.ResolvedServicePort) string {
+	if port.MappedAddress != "" {
+		return port.MappedAddress
+	}
+	return strconv.FormatUint(uint64(port.MappedPort), 10)
+}
+
+// probeTarget returns true if address accepts connections
+func probeTarget(network, address string) bool {
+	conn, err := net.DialTimeout(network, address, targetProbeTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
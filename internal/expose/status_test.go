@@ -0,0 +1,67 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package expose.
+package expose
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestServiceForward_Status(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// a port that nothing listens on
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port //nolint:errcheck // Why: test
+	closed.Close()
+
+	port := func(remote, local int) kube.ResolvedServicePort {
+		return kube.ResolvedServicePort{
+			ServicePort: corev1.ServicePort{TargetPort: intstr.FromInt(remote)},
+			MappedPort:  uint(local), //nolint:gosec // Why: test
+		}
+	}
+
+	p := &ServiceForward{
+		ServiceName: "app",
+		Namespace:   "default",
+		Ports:       []kube.ResolvedServicePort{port(8080, l.Addr().(*net.TCPAddr).Port), port(9090, closedPort)}, //nolint:errcheck // Why: test
+		objects:     []disabledObject{{Group: "apps", Resource: "deployments", Namespace: "default", Name: "app"}},
+		status:      tunnelStatus{state: TunnelStateStarting},
+	}
+	p.status.setPod("localizer-app-abcde")
+	p.status.reconnecting(errors.New("connection reset"))
+	p.status.setState(TunnelStateConnected)
+
+	expected := Status{
+		Namespace:  "default",
+		Service:    "app",
+		Mode:       ModeReplace,
+		Objects:    []string{"deployments.apps/default/app"},
+		Pod:        "localizer-app-abcde",
+		State:      TunnelStateConnected,
+		Reconnects: 1,
+		LastError:  "connection reset",
+		Targets: []TargetStatus{
+			{RemotePort: 8080, Address: l.Addr().String(), Reachable: true},
+			{RemotePort: 9090, Address: closed.Addr().String(), Reachable: false},
+		},
+	}
+	if diff := cmp.Diff(expected, p.Status()); diff != "" {
+		t.Errorf("Status() mismatch (-want +got):\n%s", diff)
+	}
+}
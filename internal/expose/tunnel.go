@@ -0,0 +1,37 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the transports used to tunnel traffic from expose pods.
+package expose
+
+import (
+	"context"
+
+	"github.com/getoutreach/localizer/internal/agent"
+	"github.com/getoutreach/localizer/internal/ssh"
+)
+
+// Tunnel is a reverse tunnel from an expose pod to the local machine.
+// It's implemented by ssh.Client and agent.Client.
+type Tunnel interface {
+	// Start starts the tunnel, blocking until it dies or the context is
+	// canceled.
+	Start(ctx context.Context, serviceKey string) error
+
+	// Ready returns a channel that's closed once the tunnel is
+	// forwarding traffic.
+	Ready() <-chan struct{}
+
+	// Active returns the number of connections currently being
+	// tunneled.
+	Active() int
+}
+
+// newTunnel creates the reverse tunnel client matching the tunnel image
+// of the expose pod. The tunnel server is expected to be reachable on
+// localPort, and ports are in the format accepted by ssh.ParsePortMap.
+func (p *ServiceForward) newTunnel(localPort int, ports []string) (Tunnel, error) {
+	if p.PodOptions.TunnelImage.UsesSSH() {
+		return ssh.NewReverseTunnelClient(p.log, "127.0.0.1", localPort, ports, p.creds, p.c.capture)
+	}
+	return agent.NewReverseTunnelClient(p.log, "127.0.0.1", localPort, ports, p.creds, p.c.capture)
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
 
 	"github.com/getoutreach/localizer/internal/kevents"
 	"github.com/getoutreach/localizer/internal/reflectconversions"
@@ -30,7 +29,6 @@ import (
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/transport/spdy"
 
 	// Needed for external authenticators
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -68,19 +66,20 @@ func GetKubeClient(contextName string) (*rest.Config, kubernetes.Interface, erro
 	return config, client, nil
 }
 
+// CreatePortForward creates a port-forward to a pod, listening on ip for
+// ports in the local:remote format, tunneled over transport
 func CreatePortForward(ctx context.Context, r rest.Interface, rc *rest.Config,
-	p *corev1.Pod, ip string, ports []string) (*portforward.PortForwarder, error) {
+	p *corev1.Pod, ip string, ports []string, transport PortForwardTransport) (*portforward.PortForwarder, error) {
 	req := r.Post().
 		Resource("pods").
 		Namespace(p.Namespace).
 		Name(p.Name).
 		SubResource("portforward")
 
-	transport, upgrader, err := spdy.RoundTripperFor(rc)
+	dialer, err := NewPortForwardDialer(rc, req.URL(), transport)
 	if err != nil {
 		return nil, err
 	}
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
 
 	return portforward.NewOnAddresses(dialer, []string{ip}, ports, ctx.Done(), nil, io.Discard, io.Discard)
 }
@@ -95,6 +94,11 @@ type ResolvedServicePort struct {
 	// MappedPort is the locally mapped port that this should have
 	// defaults to the targetPort
 	MappedPort uint
+
+	// MappedAddress overrides MappedPort when exposing, sending traffic
+	// to another local address, e.g. a Unix socket. It's in the format
+	// accepted by ssh.ParseTarget.
+	MappedAddress string
 }
 
 // ResolveServicePorts converts named ports into their true
@@ -119,6 +123,7 @@ func ResolveServicePorts(log logrus.FieldLogger, s *corev1.Service) ([]ResolvedS
 				"",
 				// nolint: gosec // Why: ports are never negative
 				uint(sp.Port),
+				"",
 			}
 		}
 		return servicePorts, nil
@@ -156,6 +161,7 @@ func ResolveServicePorts(log logrus.FieldLogger, s *corev1.Service) ([]ResolvedS
 			original,
 			// nolint: gosec // Why: ports are never negative
 			uint(p.TargetPort.IntValue()),
+			"",
 		}
 	}
 
@@ -225,51 +231,11 @@ func ResolveServicePortsFromControllers(log logrus.FieldLogger, s *corev1.Servic
 	// without a lot of complexity that doesn't seem warranted
 	controller := controllers[0]
 
-	v, err := conversion.EnforcePtr(controller)
-	if err != nil {
-		return nil, err
-	}
-	err = reflectconversions.EnforceStruct(v)
-	if err != nil {
-		return nil, err
-	}
-
-	v = v.FieldByName("Spec")
-	if !v.IsValid() {
-		return nil, fmt.Errorf("struct lacks field Spec")
-	}
-	err = reflectconversions.EnforceStruct(v)
-	if err != nil {
-		return nil, err
-	}
-
-	v = v.FieldByName("Template")
-	if !v.IsValid() {
-		return nil, fmt.Errorf("struct lacks field Template")
-	}
-	err = reflectconversions.EnforceStruct(v)
-	if err != nil {
-		return nil, err
-	}
-
-	v = v.FieldByName("Spec")
-	if !v.IsValid() {
-		return nil, fmt.Errorf("struct lacks field Spec")
-	}
-	err = reflectconversions.EnforceStruct(v)
+	template, err := GetPodTemplate(controller)
 	if err != nil {
 		return nil, err
 	}
-
-	v = v.FieldByName("Containers")
-	if !v.IsValid() {
-		return nil, fmt.Errorf("struct lacks field Spec")
-	}
-
-	containers, ok := v.Interface().([]corev1.Container)
-	if !ok {
-		return nil, fmt.Errorf("expected Containers to be []*corev1.Container, got %v", v.Type())
-	}
+	containers := template.Spec.Containers
 
 	ports := make(map[string]int)
 	for i := range containers {
@@ -304,6 +270,7 @@ func ResolveServicePortsFromControllers(log logrus.FieldLogger, s *corev1.Servic
 			original,
 			// nolint: gosec // Why: ports are never negative
 			uint(p.TargetPort.IntValue()),
+			"",
 		}
 	}
 
@@ -0,0 +1,240 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains helpers for finding the workloads that own a service's pods.
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getoutreach/localizer/internal/kevents"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Owner is the top-most controller of a pod backing a service, or the
+// pod itself if it has no controller.
+type Owner struct {
+	// Resource is the resource of the owner, e.g. apps/v1 deployments
+	Resource schema.GroupVersionResource
+
+	// Kind is the kind of the owner, e.g. Deployment
+	Kind string
+
+	Namespace string
+	Name      string
+
+	// Object is the owner at the time it was found
+	Object *unstructured.Unstructured
+}
+
+// IsPod returns true if the owner is a pod without a controller
+func (o *Owner) IsPod() bool {
+	return o.Resource.Group == "" && o.Resource.Resource == "pods"
+}
+
+// PodSpec returns the spec of the pod, or the pod template of the
+// controller, assuming that it's at spec.template like it is for every
+// built-in controller
+func (o *Owner) PodSpec() (*corev1.PodSpec, error) {
+	fields := []string{"spec", "template", "spec"}
+	if o.IsPod() {
+		fields = []string{"spec"}
+	}
+
+	raw, ok, err := unstructured.NestedMap(o.Object.Object, fields...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read pod spec")
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s %s/%s has no pod template", o.Kind, o.Namespace, o.Name)
+	}
+
+	spec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, spec); err != nil {
+		return nil, errors.Wrap(err, "failed to convert pod spec")
+	}
+	return spec, nil
+}
+
+// podResource is the resource of pods
+var podResource = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// FindServiceOwners returns the top-most controllers of the pods backing a
+// service, found by walking their owner references. This supports any
+// type of controller, including custom resources. Pods without a
+// controller are returned as-is.
+//
+// Pods are found through the service's endpoints, falling back to the
+// service's selector. If no pods are found, e.g. because the service's
+// workloads are scaled down, then FindControllersForService is used.
+func FindServiceOwners(ctx context.Context, log logrus.FieldLogger, dyn dynamic.Interface, rm meta.RESTMapper,
+	s *corev1.Service) ([]Owner, error) {
+	pods, err := servicePods(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pods) == 0 {
+		log.Debug("service has no pods, falling back to finding controllers by pod template")
+		return ownersFromControllers(log, s)
+	}
+
+	return OwnersForPods(ctx, log, dyn, rm, pods)
+}
+
+// OwnersForPods returns the distinct top-most controllers of the given
+// pods, or the pods themselves if they have no controller.
+func OwnersForPods(ctx context.Context, log logrus.FieldLogger, dyn dynamic.Interface, rm meta.RESTMapper,
+	pods []*corev1.Pod) ([]Owner, error) {
+	seen := make(map[types.UID]bool)
+	owners := make([]Owner, 0)
+	for _, po := range pods {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(po)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert pod")
+		}
+
+		owner, err := topOwner(ctx, log, dyn, rm, Owner{
+			Resource:  podResource,
+			Kind:      "Pod",
+			Namespace: po.Namespace,
+			Name:      po.Name,
+			Object:    &unstructured.Unstructured{Object: obj},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[owner.Object.GetUID()] {
+			continue
+		}
+		seen[owner.Object.GetUID()] = true
+		owners = append(owners, owner)
+	}
+
+	return owners, nil
+}
+
+// servicePods returns the pods backing a service, from the endpoints of
+// the service or, if it has none, the pods matching its selector.
+func servicePods(s *corev1.Service) ([]*corev1.Pod, error) {
+	podStore := kevents.GlobalCache.Core().V1().Pods().Informer().GetStore()
+
+	pods := make([]*corev1.Pod, 0)
+	obj, exists, err := kevents.GlobalCache.Core().V1().Endpoints().Informer().GetStore().GetByKey(s.Namespace + "/" + s.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get endpoints")
+	}
+	if exists {
+		//nolint:errcheck // Why: the endpoints store only contains endpoints
+		for _, subset := range obj.(*corev1.Endpoints).Subsets {
+			addresses := append(append([]corev1.EndpointAddress{}, subset.Addresses...), subset.NotReadyAddresses...)
+			for _, addr := range addresses {
+				if addr.TargetRef == nil || addr.TargetRef.Kind != "Pod" {
+					continue
+				}
+
+				obj, exists, err := podStore.GetByKey(addr.TargetRef.Namespace + "/" + addr.TargetRef.Name)
+				if err != nil || !exists {
+					continue
+				}
+				pods = append(pods, obj.(*corev1.Pod)) //nolint:errcheck // Why: the pod store only contains pods
+			}
+		}
+	}
+
+	if len(pods) != 0 || len(s.Spec.Selector) == 0 {
+		return pods, nil
+	}
+
+	selector := labels.SelectorFromSet(s.Spec.Selector)
+	for _, obj := range podStore.List() {
+		po := obj.(*corev1.Pod) //nolint:errcheck // Why: the pod store only contains pods
+		if po.Namespace == s.Namespace && selector.Matches(labels.Set(po.Labels)) {
+			pods = append(pods, po)
+		}
+	}
+	return pods, nil
+}
+
+// topOwner follows the controller owner references of an object until it
+// reaches an object without a controller. If an owner can't be found,
+// e.g. because it's being deleted, the last found object is returned.
+func topOwner(ctx context.Context, log logrus.FieldLogger, dyn dynamic.Interface, rm meta.RESTMapper, o Owner) (Owner, error) {
+	for {
+		ref := metav1.GetControllerOfNoCopy(o.Object)
+		if ref == nil {
+			return o, nil
+		}
+
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return Owner{}, errors.Wrapf(err, "failed to parse owner apiVersion %q", ref.APIVersion)
+		}
+
+		mapping, err := rm.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: ref.Kind}, gv.Version)
+		if err != nil {
+			return Owner{}, errors.Wrapf(err, "failed to find resource for %s", gv.WithKind(ref.Kind))
+		}
+
+		obj, err := dyn.Resource(mapping.Resource).Namespace(o.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			log.WithField("owner", ref.Name).Debug("owner no longer exists")
+			return o, nil
+		} else if err != nil {
+			return Owner{}, errors.Wrapf(err, "failed to get %s %s", ref.Kind, ref.Name)
+		}
+
+		o = Owner{
+			Resource:  mapping.Resource,
+			Kind:      ref.Kind,
+			Namespace: o.Namespace,
+			Name:      ref.Name,
+			Object:    obj,
+		}
+	}
+}
+
+// ownersFromControllers converts the controllers found by
+// FindControllersForService into owners
+func ownersFromControllers(log logrus.FieldLogger, s *corev1.Service) ([]Owner, error) {
+	controllers, err := FindControllersForService(log, s)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make([]Owner, 0, len(controllers))
+	for _, c := range controllers {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(c)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert controller")
+		}
+
+		kind, resource := "Deployment", "deployments"
+		if _, ok := c.(*appsv1.StatefulSet); ok {
+			kind, resource = "StatefulSet", "statefulsets"
+		}
+
+		u := &unstructured.Unstructured{Object: obj}
+		owners = append(owners, Owner{
+			Resource:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: resource},
+			Kind:      kind,
+			Namespace: u.GetNamespace(),
+			Name:      u.GetName(),
+			Object:    u,
+		})
+	}
+	return owners, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package kube.
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+)
+
+// object returns an unstructured object owned by the given owner
+func object(apiVersion, kind, name string, uid types.UID, owner *unstructured.Unstructured) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace("default")
+	u.SetName(name)
+	u.SetUID(uid)
+	if owner != nil {
+		u.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: owner.GetAPIVersion(),
+			Kind:       owner.GetKind(),
+			Name:       owner.GetName(),
+			UID:        owner.GetUID(),
+			Controller: ptr.To(true),
+		}})
+	}
+	return u
+}
+
+func TestOwnersForPods(t *testing.T) {
+	rollouts := schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+	replicaSets := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+
+	rm := meta.NewDefaultRESTMapper(nil)
+	rm.Add(rollouts.GroupVersion().WithKind("Rollout"), meta.RESTScopeNamespace)
+	rm.Add(replicaSets.GroupVersion().WithKind("ReplicaSet"), meta.RESTScopeNamespace)
+
+	rollout := object("argoproj.io/v1alpha1", "Rollout", "app", "rollout", nil)
+	rs := object("apps/v1", "ReplicaSet", "app-abc", "rs", rollout)
+	deleted := object("apps/v1", "ReplicaSet", "deleted", "deleted", nil)
+
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		rollouts:    "RolloutList",
+		replicaSets: "ReplicaSetList",
+	}, rollout, rs)
+
+	pod := func(name string, owner *unstructured.Unstructured) *corev1.Pod {
+		po := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)}}
+		if owner != nil {
+			po.OwnerReferences = object("v1", "Pod", name, "", owner).GetOwnerReferences()
+		}
+		return po
+	}
+
+	owners, err := OwnersForPods(context.Background(), logrus.New(), dyn, rm, []*corev1.Pod{
+		pod("app-abc-1", rs),
+		pod("app-abc-2", rs),
+		pod("bare", nil),
+		pod("orphan", deleted),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		Resource schema.GroupVersionResource
+		Kind     string
+		Name     string
+		IsPod    bool
+	}
+	got := make([]result, len(owners))
+	for i := range owners {
+		got[i] = result{owners[i].Resource, owners[i].Kind, owners[i].Name, owners[i].IsPod()}
+	}
+
+	expected := []result{
+		{rollouts, "Rollout", "app", false},
+		{podResource, "Pod", "bare", true},
+		{podResource, "Pod", "orphan", true},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("OwnersForPods() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOwner_PodSpec(t *testing.T) {
+	containers := []interface{}{map[string]interface{}{"name": "app", "image": "app:v1"}}
+
+	rollout := object("argoproj.io/v1alpha1", "Rollout", "app", "rollout", nil)
+	rollout.Object["spec"] = map[string]interface{}{
+		"template": map[string]interface{}{"spec": map[string]interface{}{"containers": containers}},
+	}
+	pod := object("v1", "Pod", "bare", "bare", nil)
+	pod.Object["spec"] = map[string]interface{}{"containers": containers}
+
+	expected := &corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:v1"}}}
+	for _, o := range []Owner{
+		{Resource: schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}, Kind: "Rollout", Object: rollout},
+		{Resource: podResource, Kind: "Pod", Object: pod},
+	} {
+		spec, err := o.PodSpec()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(expected, spec); diff != "" {
+			t.Errorf("PodSpec() of %s mismatch (-want +got):\n%s", o.Kind, diff)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the transports port-forwards are tunneled over.
+package kube
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwardTransport is the protocol port-forwards are tunneled over
+type PortForwardTransport string
+
+// This block contains the supported port-forward transports
+const (
+	// PortForwardTransportWebSocket tunnels port-forwards over
+	// WebSockets, which works through HTTP proxies and load balancers.
+	// It's supported by Kubernetes 1.30+, SPDY is used when the API
+	// server doesn't support it.
+	PortForwardTransportWebSocket PortForwardTransport = "websocket"
+
+	// PortForwardTransportSPDY tunnels port-forwards over SPDY
+	PortForwardTransportSPDY PortForwardTransport = "spdy"
+)
+
+// ParsePortForwardTransport parses a port-forward transport, an empty
+// string is the default transport, websocket
+func ParsePortForwardTransport(s string) (PortForwardTransport, error) {
+	switch t := PortForwardTransport(s); t {
+	case "":
+		return PortForwardTransportWebSocket, nil
+	case PortForwardTransportWebSocket, PortForwardTransportSPDY:
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown port-forward transport %q, expected one of: websocket, spdy", s)
+}
+
+// NewPortForwardDialer creates a dialer for the port-forward subresource
+// of a pod at u, using transport
+func NewPortForwardDialer(rc *rest.Config, u *url.URL, transport PortForwardTransport) (httpstream.Dialer, error) {
+	roundTripper, upgrader, err := spdy.RoundTripperFor(rc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create spdy round tripper")
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, "POST", u)
+	if transport == PortForwardTransportSPDY {
+		return dialer, nil
+	}
+
+	tunnelingDialer, err := portforward.NewSPDYOverWebsocketDialer(u, rc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create websocket dialer")
+	}
+
+	// API servers that don't support WebSockets, and proxies that don't
+	// support upgrading to them, fail the upgrade
+	return portforward.NewFallbackDialer(tunnelingDialer, dialer, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	}), nil
+}
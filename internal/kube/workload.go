@@ -0,0 +1,412 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains helpers for reading the configuration of workloads.
+package kube
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getoutreach/localizer/internal/reflectconversions"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/client-go/kubernetes"
+)
+
+// validEnvName matches names that are valid environment variable names,
+// keys of ConfigMaps and Secrets that don't match are skipped by the
+// kubelet when used with envFrom.
+var validEnvName = regexp.MustCompile(`^[-._a-zA-Z][-._a-zA-Z0-9]*$`)
+
+// GetPodTemplate returns the pod template of a controller, assumes that
+// the path is Spec.Template
+func GetPodTemplate(obj interface{}) (*corev1.PodTemplateSpec, error) {
+	v, err := conversion.EnforcePtr(obj)
+	if err != nil {
+		return nil, err
+	}
+	err = reflectconversions.EnforceStruct(v)
+	if err != nil {
+		return nil, err
+	}
+
+	v = v.FieldByName("Spec")
+	if !v.IsValid() {
+		return nil, fmt.Errorf("struct lacks field Spec")
+	}
+	err = reflectconversions.EnforceStruct(v)
+	if err != nil {
+		return nil, err
+	}
+
+	v = v.FieldByName("Template")
+	if !v.IsValid() {
+		return nil, fmt.Errorf("struct lacks field Template")
+	}
+
+	template, ok := v.Addr().Interface().(*corev1.PodTemplateSpec)
+	if !ok {
+		return nil, fmt.Errorf("expected Template to be corev1.PodTemplateSpec, got %v", v.Type())
+	}
+
+	return template, nil
+}
+
+// FindContainer returns the container with the given name from a pod
+// spec. If name is empty, the first container is returned.
+func FindContainer(spec *corev1.PodSpec, name string) (*corev1.Container, error) {
+	if len(spec.Containers) == 0 {
+		return nil, fmt.Errorf("pod spec has no containers")
+	}
+
+	if name == "" {
+		return &spec.Containers[0], nil
+	}
+
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == name {
+			return &spec.Containers[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find container %q", name)
+}
+
+// configSource fetches, and caches, ConfigMaps and Secrets referenced
+// by a pod template.
+type configSource struct {
+	k         kubernetes.Interface
+	namespace string
+
+	configMaps map[string]*corev1.ConfigMap
+	secrets    map[string]*corev1.Secret
+}
+
+// newConfigSource creates a configSource for the given namespace
+func newConfigSource(k kubernetes.Interface, namespace string) *configSource {
+	return &configSource{
+		k:          k,
+		namespace:  namespace,
+		configMaps: make(map[string]*corev1.ConfigMap),
+		secrets:    make(map[string]*corev1.Secret),
+	}
+}
+
+// configMapData returns the data of a ConfigMap. If the ConfigMap
+// doesn't exist and optional is true, nil is returned.
+func (c *configSource) configMapData(ctx context.Context, name string, optional *bool) (map[string]string, error) {
+	cm, ok := c.configMaps[name]
+	if !ok {
+		var err error
+		cm, err = c.k.CoreV1().ConfigMaps(c.namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) && optional != nil && *optional {
+				return nil, nil
+			}
+			return nil, errors.Wrapf(err, "failed to get configmap %q", name)
+		}
+		c.configMaps[name] = cm
+	}
+
+	data := make(map[string]string, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.BinaryData {
+		data[k] = string(v)
+	}
+	for k, v := range cm.Data {
+		data[k] = v
+	}
+	return data, nil
+}
+
+// secretData returns the data of a Secret. If the Secret doesn't exist
+// and optional is true, nil is returned.
+func (c *configSource) secretData(ctx context.Context, name string, optional *bool) (map[string]string, error) {
+	s, ok := c.secrets[name]
+	if !ok {
+		var err error
+		s, err = c.k.CoreV1().Secrets(c.namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) && optional != nil && *optional {
+				return nil, nil
+			}
+			return nil, errors.Wrapf(err, "failed to get secret %q", name)
+		}
+		c.secrets[name] = s
+	}
+
+	data := make(map[string]string, len(s.Data))
+	for k, v := range s.Data {
+		data[k] = string(v)
+	}
+	return data, nil
+}
+
+// ResolveContainerEnv resolves the environment of a container in the
+// same way the kubelet would, fetching ConfigMaps and Secrets from the
+// provided namespace. Values that can only be known inside of a running
+// pod, like most fieldRefs and resourceFieldRefs, are skipped.
+func ResolveContainerEnv(ctx context.Context, log logrus.FieldLogger, k kubernetes.Interface,
+	namespace string, container *corev1.Container) (map[string]string, error) {
+	src := newConfigSource(k, namespace)
+	env := make(map[string]string)
+
+	for _, from := range container.EnvFrom {
+		var data map[string]string
+		var err error
+		switch {
+		case from.ConfigMapRef != nil:
+			data, err = src.configMapData(ctx, from.ConfigMapRef.Name, from.ConfigMapRef.Optional)
+		case from.SecretRef != nil:
+			data, err = src.secretData(ctx, from.SecretRef.Name, from.SecretRef.Optional)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range data {
+			if !validEnvName.MatchString(from.Prefix + key) {
+				log.WithField("key", key).Debug("skipping invalid environment variable name")
+				continue
+			}
+			env[from.Prefix+key] = value
+		}
+	}
+
+	for i := range container.Env {
+		e := &container.Env[i]
+		if e.ValueFrom == nil {
+			env[e.Name] = expandEnv(e.Value, env)
+			continue
+		}
+
+		var data map[string]string
+		var kind, name, key string
+		var optional *bool
+		var err error
+		switch {
+		case e.ValueFrom.ConfigMapKeyRef != nil:
+			ref := e.ValueFrom.ConfigMapKeyRef
+			kind, name, key, optional = "configmap", ref.Name, ref.Key, ref.Optional
+			data, err = src.configMapData(ctx, ref.Name, ref.Optional)
+		case e.ValueFrom.SecretKeyRef != nil:
+			ref := e.ValueFrom.SecretKeyRef
+			kind, name, key, optional = "secret", ref.Name, ref.Key, ref.Optional
+			data, err = src.secretData(ctx, ref.Name, ref.Optional)
+		case e.ValueFrom.FieldRef != nil && e.ValueFrom.FieldRef.FieldPath == "metadata.namespace":
+			env[e.Name] = namespace
+			continue
+		default:
+			log.WithField("name", e.Name).Debug("skipping environment variable only known inside of a pod")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value, ok := data[key]
+		if !ok {
+			// the kubelet refuses to start the container in this case
+			if optional == nil || !*optional {
+				return nil, fmt.Errorf("environment variable %s references key %q of %s %q, which doesn't exist", e.Name, key, kind, name)
+			}
+			continue
+		}
+		env[e.Name] = value
+	}
+
+	return env, nil
+}
+
+// expandEnv expands $(VAR) references in s using the provided
+// variables, in the same way the kubelet does. References to unknown
+// variables are left as-is and $$ escapes a $.
+func expandEnv(s string, vars map[string]string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '(':
+			end := strings.IndexByte(s[i+2:], ')')
+			if end == -1 {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+
+			name := s[i+2 : i+2+end]
+			if value, ok := vars[name]; ok {
+				b.WriteString(value)
+			} else {
+				b.WriteString(s[i : i+3+end])
+			}
+			i += 2 + end
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String()
+}
+
+// File is a file that would be mounted into a container from a
+// ConfigMap, Secret or projected volume.
+type File struct {
+	// Path is the absolute path of the file inside of the container
+	Path string
+
+	// Data is the contents of the file
+	Data []byte
+
+	// Mode is the permission bits of the file
+	Mode int32
+
+	// Secret is true if the data of the file is from a Secret
+	Secret bool
+}
+
+// defaultFileMode is the mode the kubelet uses for projected files when
+// no defaultMode is set on the volume.
+const defaultFileMode = int32(0o644)
+
+// ResolveContainerFiles resolves the files that would be mounted into a
+// container from ConfigMap, Secret and projected volumes, fetching them
+// from the provided namespace. Other volume types, as well as downwardAPI
+// and serviceAccountToken projections, are skipped. The returned files are
+// sorted by path.
+func ResolveContainerFiles(ctx context.Context, log logrus.FieldLogger, k kubernetes.Interface,
+	namespace string, spec *corev1.PodSpec, container *corev1.Container) ([]File, error) {
+	src := newConfigSource(k, namespace)
+
+	volumes := make(map[string]*corev1.Volume, len(spec.Volumes))
+	for i := range spec.Volumes {
+		volumes[spec.Volumes[i].Name] = &spec.Volumes[i]
+	}
+
+	files := make([]File, 0)
+	for _, mount := range container.VolumeMounts {
+		v, ok := volumes[mount.Name]
+		if !ok {
+			continue
+		}
+
+		contents, err := src.volumeFiles(ctx, log, v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve volume %q", v.Name)
+		}
+
+		// subPath mounts a single file, or directory, from the volume at
+		// the mount path. Only files are supported here.
+		if mount.SubPath != "" {
+			if f, ok := contents[mount.SubPath]; ok {
+				f.Path = mount.MountPath
+				files = append(files, f)
+			}
+			continue
+		}
+
+		for p, f := range contents {
+			f.Path = path.Join(mount.MountPath, p)
+			files = append(files, f)
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// volumeFiles returns the files, keyed by their path relative to the
+// volume root, that the kubelet would create for a volume. Volumes that
+// aren't backed by a ConfigMap or Secret return no files.
+func (c *configSource) volumeFiles(ctx context.Context, log logrus.FieldLogger, v *corev1.Volume) (map[string]File, error) {
+	files := make(map[string]File)
+
+	switch {
+	case v.ConfigMap != nil:
+		data, err := c.configMapData(ctx, v.ConfigMap.Name, v.ConfigMap.Optional)
+		if err != nil {
+			return nil, err
+		}
+		projectKeys(files, data, v.ConfigMap.Items, v.ConfigMap.DefaultMode, false)
+	case v.Secret != nil:
+		data, err := c.secretData(ctx, v.Secret.SecretName, v.Secret.Optional)
+		if err != nil {
+			return nil, err
+		}
+		projectKeys(files, data, v.Secret.Items, v.Secret.DefaultMode, true)
+	case v.Projected != nil:
+		for _, s := range v.Projected.Sources {
+			var data map[string]string
+			var items []corev1.KeyToPath
+			var secret bool
+			var err error
+			switch {
+			case s.ConfigMap != nil:
+				items = s.ConfigMap.Items
+				data, err = c.configMapData(ctx, s.ConfigMap.Name, s.ConfigMap.Optional)
+			case s.Secret != nil:
+				items, secret = s.Secret.Items, true
+				data, err = c.secretData(ctx, s.Secret.Name, s.Secret.Optional)
+			default:
+				log.WithField("volume", v.Name).Debug("skipping projected source only known inside of a pod")
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			projectKeys(files, data, items, v.Projected.DefaultMode, secret)
+		}
+	default:
+		log.WithField("volume", v.Name).Debug("skipping unsupported volume type")
+	}
+
+	return files, nil
+}
+
+// projectKeys adds the provided data to files in the same way the kubelet
+// does for ConfigMap and Secret volumes. If items is set, only the listed
+// keys are projected, at their configured path. secret is true if the
+// data is from a Secret.
+func projectKeys(files map[string]File, data map[string]string, items []corev1.KeyToPath, defaultMode *int32, secret bool) {
+	mode := defaultFileMode
+	if defaultMode != nil {
+		mode = *defaultMode
+	}
+
+	if len(items) == 0 {
+		for key, value := range data {
+			files[key] = File{Data: []byte(value), Mode: mode, Secret: secret}
+		}
+		return
+	}
+
+	for _, item := range items {
+		value, ok := data[item.Key]
+		if !ok {
+			continue
+		}
+
+		f := File{Data: []byte(value), Mode: mode, Secret: secret}
+		if item.Mode != nil {
+			f.Mode = *item.Mode
+		}
+		files[item.Path] = f
+	}
+}
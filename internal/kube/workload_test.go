@@ -0,0 +1,183 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package kube.
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestResolveContainerEnv(t *testing.T) {
+	k := fake.NewClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"},
+			Data:       map[string]string{"LOG_LEVEL": "debug", "not valid": "skipped"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "creds"},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		},
+	)
+
+	container := &corev1.Container{
+		EnvFrom: []corev1.EnvFromSource{
+			{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}}},
+			{
+				Prefix:    "OPTIONAL_",
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Optional: ptr.To(true)},
+			},
+		},
+		Env: []corev1.EnvVar{
+			{Name: "HOST", Value: "postgres"},
+			{Name: "URL", Value: "postgres://$(HOST):5432/$(UNKNOWN)?cost=$$5"},
+			{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "creds"}, Key: "password"},
+			}},
+			{Name: "NAMESPACE", ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+			}},
+			{Name: "POD_IP", ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+			}},
+		},
+	}
+
+	env, err := ResolveContainerEnv(context.Background(), logrus.New(), k, "default", container)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"LOG_LEVEL": "debug",
+		"HOST":      "postgres",
+		"URL":       "postgres://postgres:5432/$(UNKNOWN)?cost=$5",
+		"PASSWORD":  "hunter2",
+		"NAMESPACE": "default",
+	}
+	if diff := cmp.Diff(expected, env); diff != "" {
+		t.Errorf("unexpected env (-want +got):\n%s", diff)
+	}
+}
+
+func TestResolveContainerEnv_Missing(t *testing.T) {
+	k := fake.NewClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"},
+		Data:       map[string]string{"LOG_LEVEL": "debug"},
+	})
+	keyRef := func(name, key string, optional *bool) []corev1.EnvVar {
+		return []corev1.EnvVar{{Name: "VALUE", ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key, Optional: optional,
+			},
+		}}}
+	}
+
+	tests := []struct {
+		name      string
+		container corev1.Container
+		wantErr   bool
+	}{
+		{
+			name: "required configmap",
+			container: corev1.Container{EnvFrom: []corev1.EnvFromSource{
+				{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}}},
+			}},
+			wantErr: true,
+		},
+		{name: "required key", container: corev1.Container{Env: keyRef("config", "missing", nil)}, wantErr: true},
+		{name: "key not marked optional", container: corev1.Container{Env: keyRef("config", "missing", ptr.To(false))}, wantErr: true},
+		{name: "optional key", container: corev1.Container{Env: keyRef("config", "missing", ptr.To(true))}},
+		{name: "optional configmap", container: corev1.Container{Env: keyRef("missing", "LOG_LEVEL", ptr.To(true))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := ResolveContainerEnv(context.Background(), logrus.New(), k, "default", &tt.container)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveContainerEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, ok := env["VALUE"]; ok {
+				t.Errorf("ResolveContainerEnv() set a missing key, got %v", env)
+			}
+		})
+	}
+}
+
+func TestGetPodTemplate(t *testing.T) {
+	d := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			},
+		},
+	}
+
+	template, err := GetPodTemplate(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if template != &d.Spec.Template {
+		t.Error("expected the pod template of the deployment to be returned")
+	}
+}
+
+func TestResolveContainerFiles(t *testing.T) {
+	k := fake.NewClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"},
+			Data:       map[string]string{"config.yaml": "a: b", "other.yaml": "c: d"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "creds"},
+			Data:       map[string][]byte{"token": []byte("hunter2")},
+		},
+	)
+
+	spec := &corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "config"},
+				Items:                []corev1.KeyToPath{{Key: "config.yaml", Path: "app/config.yaml"}},
+			}}},
+			{Name: "projected", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+				DefaultMode: ptr.To(int32(0o400)),
+				Sources: []corev1.VolumeProjection{
+					{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "creds"}}},
+					{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "sa-token"}},
+				},
+			}}},
+			{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		},
+	}
+	container := &corev1.Container{
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "config", MountPath: "/etc"},
+			{Name: "config", MountPath: "/etc/single.yaml", SubPath: "app/config.yaml"},
+			{Name: "projected", MountPath: "/var/run/secrets"},
+			{Name: "scratch", MountPath: "/tmp"},
+		},
+	}
+
+	files, err := ResolveContainerFiles(context.Background(), logrus.New(), k, "default", spec, container)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []File{
+		{Path: "/etc/app/config.yaml", Data: []byte("a: b"), Mode: 0o644},
+		{Path: "/etc/single.yaml", Data: []byte("a: b"), Mode: 0o644},
+		{Path: "/var/run/secrets/token", Data: []byte("hunter2"), Mode: 0o400, Secret: true},
+	}
+	if diff := cmp.Diff(expected, files); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+}
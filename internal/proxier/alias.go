@@ -0,0 +1,125 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains helpers for managing loopback aliases.
+package proxier
+
+import (
+	"net/netip"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// loopbackAliasesEnabled returns true if we should be managing aliases
+// on the loopback interface on this platform.
+func loopbackAliasesEnabled() bool {
+	return os.Getenv("DISABLE_LOOPBACK_ALIAS") == ""
+}
+
+// loopbackAliasArgs returns the command used to add, or remove, the
+// provided IP address as an alias on the loopback interface. If no
+// command is needed on this platform, then nil is returned.
+//
+// On darwin every address needs to be aliased onto lo0. On linux lo
+// routes the full IPv4 127.0.0.0/8 range, but IPv6 only routes ::1,
+// so any other IPv6 address needs to be added to lo.
+func loopbackAliasArgs(ip netip.Addr, remove bool) []string {
+	switch runtime.GOOS {
+	case "darwin":
+		op := "alias"
+		if remove {
+			op = "-alias"
+		}
+
+		if ip.Is6() {
+			return []string{"ifconfig", "lo0", "inet6", op, ip.String()}
+		}
+
+		if remove {
+			return []string{"ifconfig", "lo0", op, ip.String()}
+		}
+		return []string{"ifconfig", "lo0", op, ip.String(), "up"}
+	case "linux":
+		if !ip.Is6() || ip.IsLoopback() {
+			return nil
+		}
+
+		op := "add"
+		if remove {
+			op = "del"
+		}
+		return []string{"ip", "-6", "addr", op, ip.String() + "/128", "dev", "lo"}
+	}
+
+	return nil
+}
+
+// addLoopbackAlias ensures that the provided IP address can be listened
+// on by adding it to the loopback interface, if required.
+func addLoopbackAlias(ip netip.Addr) error {
+	if !loopbackAliasesEnabled() {
+		return nil
+	}
+
+	args := loopbackAliasArgs(ip, false)
+	if args == nil {
+		return nil
+	}
+
+	//nolint:gosec // Why: arguments are constructed from a parsed IP address
+	if err := exec.Command(args[0], args[1:]...).Run(); err != nil {
+		return errors.Wrapf(err, "failed to create ip link for %s", ip)
+	}
+
+	return nil
+}
+
+// removeLoopbackAlias removes an alias created by addLoopbackAlias.
+func removeLoopbackAlias(ip netip.Addr) error {
+	if !loopbackAliasesEnabled() {
+		return nil
+	}
+
+	args := loopbackAliasArgs(ip, true)
+	if args == nil {
+		return nil
+	}
+
+	//nolint:gosec // Why: arguments are constructed from a parsed IP address
+	if err := exec.Command(args[0], args[1:]...).Run(); err != nil {
+		message := ""
+		var exitError *exec.ExitError
+		if ok := errors.As(err, &exitError); ok {
+			message = string(exitError.Stderr)
+		}
+		return errors.Wrapf(err, "failed to release ip alias: %s", message)
+	}
+
+	return nil
+}
+
+// loopbackAddress returns the loopback address for the given family.
+func loopbackAddress(ipv6 bool) netip.Addr {
+	if ipv6 {
+		return netip.IPv6Loopback()
+	}
+	return netip.MustParseAddr("127.0.0.1")
+}
+
+// sharesLoopbackAddress returns true if port-forwards of the given
+// family have to share the loopback address. This is the case when
+// the platform requires aliases for addresses of that family, but
+// aliases have been disabled (DISABLE_LOOPBACK_ALIAS).
+func sharesLoopbackAddress(ipv6 bool) bool {
+	if loopbackAliasesEnabled() {
+		return false
+	}
+
+	ip := netip.MustParseAddr("127.0.0.2")
+	if ipv6 {
+		ip = netip.MustParseAddr("fd00::2")
+	}
+	return loopbackAliasArgs(ip, false) != nil
+}
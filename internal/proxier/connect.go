@@ -0,0 +1,78 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the HTTP CONNECT protocol spoken by the gateway.
+package proxier
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// readConnectRequest reads an HTTP CONNECT request, other methods are
+// rejected since the gateway only tunnels connections. Replies are
+// written to w.
+func readConnectRequest(r *bufio.Reader, w io.Writer) (*proxyRequest, error) {
+	httpReq, err := http.ReadRequest(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read http request")
+	}
+
+	if httpReq.Method != http.MethodConnect {
+		writeConnectReply(w, http.StatusMethodNotAllowed) //nolint:errcheck // Why: the request failed anyways
+		return nil, fmt.Errorf("unsupported http method %s", httpReq.Method)
+	}
+
+	host, rawPort, err := net.SplitHostPort(httpReq.Host)
+	if err != nil {
+		writeConnectReply(w, http.StatusBadRequest) //nolint:errcheck // Why: the request failed anyways
+		return nil, errors.Wrapf(err, "invalid address %q", httpReq.Host)
+	}
+
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil {
+		writeConnectReply(w, http.StatusBadRequest) //nolint:errcheck // Why: the request failed anyways
+		return nil, errors.Wrapf(err, "invalid port %q", rawPort)
+	}
+
+	return &proxyRequest{
+		host: host,
+		port: uint16(port),
+		reply: func(err error) error {
+			return writeConnectReply(w, connectStatus(err))
+		},
+	}, nil
+}
+
+// writeConnectReply writes a response with status, the connection is
+// closed after every status other than 200
+func writeConnectReply(w io.Writer, status int) error {
+	headers := ""
+	if status != http.StatusOK {
+		headers = "Connection: close\r\nContent-Length: 0\r\n"
+	}
+
+	_, err := fmt.Fprintf(w, "HTTP/1.1 %d %s\r\n%s\r\n", status, http.StatusText(status), headers)
+	return err
+}
+
+// connectStatus returns the status reporting err
+func connectStatus(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, errNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errNoEndpoints):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, errRejected):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadGateway
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the listeners that accept connections for port-forwards.
+package proxier
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/capture"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// dialFunc opens a connection to the pod behind a port-forward for a port
+type dialFunc func(ctx context.Context, port *ForwardedPort) (net.Conn, error)
+
+// ConnInfo describes a connection accepted by a port-forward
+type ConnInfo struct {
+	Service ServiceInfo
+	Pod     PodInfo
+	Port    ForwardedPort
+}
+
+// ConnHook is called for every connection accepted by a port-forward,
+// before it's sent to the pod. It returns the connection to use, e.g.
+// the connection wrapped to record it, or an error to reject it.
+type ConnHook func(info *ConnInfo, conn net.Conn) (net.Conn, error)
+
+// CaptureHook returns a ConnHook that records connections to the
+// captures in registry
+func CaptureHook(registry *capture.Registry) ConnHook {
+	return func(info *ConnInfo, conn net.Conn) (net.Conn, error) {
+		// nolint: gosec // Why: ports are never larger than a uint16
+		return registry.Tap(info.Service.Key(), uint16(info.Port.Port), conn,
+			conn.RemoteAddr().String(), conn.LocalAddr().String()), nil
+	}
+}
+
+// forwarder accepts connections on the local addresses of a port-forward,
+// runs them through its hooks, and sends them to the pod through dial.
+type forwarder struct {
+	log   logrus.FieldLogger
+	info  ConnInfo
+	dial  dialFunc
+	hooks []ConnHook
+
+	listeners []net.Listener
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// newForwarder listens on the local port of every port for each address,
+// sending connections to dial until it's closed
+func newForwarder(ctx context.Context, log logrus.FieldLogger, info ConnInfo, addresses []string,
+	ports []ForwardedPort, dial dialFunc, hooks []ConnHook) (*forwarder, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &forwarder{log: log, info: info, dial: dial, hooks: hooks, cancel: cancel}
+
+	for i := range ports {
+		for _, addr := range addresses {
+			listenAddr := net.JoinHostPort(addr, strconv.FormatUint(uint64(ports[i].LocalPort), 10))
+			l, err := net.Listen("tcp", listenAddr)
+			if err != nil {
+				f.Close()
+				return nil, errors.Wrapf(err, "failed to listen on %s", listenAddr)
+			}
+			f.listeners = append(f.listeners, l)
+
+			f.wg.Add(1)
+			go f.accept(ctx, l, ports[i])
+		}
+	}
+
+	return f, nil
+}
+
+// accept handles the connections of a listener until it's closed
+func (f *forwarder) accept(ctx context.Context, l net.Listener, port ForwardedPort) {
+	defer f.wg.Done()
+
+	info := f.info
+	info.Port = port
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		go f.handle(ctx, conn, &info)
+	}
+}
+
+// handle sends a connection to the pod
+func (f *forwarder) handle(ctx context.Context, conn net.Conn, info *ConnInfo) {
+	log := f.log.WithFields(logrus.Fields{"client": conn.RemoteAddr().String(), "port": info.Port.Port})
+	start := time.Now()
+
+	for _, hook := range f.hooks {
+		hooked, err := hook(info, conn)
+		if err != nil {
+			log.WithError(err).Debug("rejected connection")
+			conn.Close()
+			return
+		}
+		conn = hooked
+	}
+	defer conn.Close()
+
+	remote, err := f.dial(ctx, &info.Port)
+	if err != nil {
+		log.WithError(err).Warn("failed to forward connection")
+		return
+	}
+
+	sent, received, err := pipe(conn, remote)
+	log = log.WithFields(logrus.Fields{"sent": sent, "received": received, "duration": time.Since(start).String()})
+	if err != nil {
+		log.WithError(err).Warn("port-forward connection failed")
+		return
+	}
+	log.Debug("port-forward connection closed")
+}
+
+// Close stops listening, and stops connections that are waiting for their
+// stream to the pod, e.g. while the port-forward is created. Open
+// connections are closed by the dialer.
+func (f *forwarder) Close() {
+	f.cancel()
+	for _, l := range f.listeners {
+		l.Close()
+	}
+	f.wg.Wait()
+}
+
+// pipe copies data between a client and the pod until the pod is done
+// sending, or either side fails. When the client is done sending, the
+// pod is told so but can keep sending. It returns the number of bytes
+// sent by the client and received from the pod.
+func pipe(client, remote net.Conn) (sent, received int64, err error) {
+	// the copy from the client may still be running when this returns
+	var sentBytes, receivedBytes atomic.Int64
+
+	remoteDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(&countingWriter{client, &receivedBytes}, remote)
+		remoteDone <- err
+	}()
+
+	localErr := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(&countingWriter{remote, &sentBytes}, client)
+		if err != nil {
+			localErr <- err
+			return
+		}
+		closeWrite(remote) //nolint:errcheck // Why: the pod may already be gone
+	}()
+
+	select {
+	case err = <-remoteDone:
+	case err = <-localErr:
+	}
+
+	// closing the remote unblocks the copy from it, the client is closed
+	// by the caller
+	if closeErr := remote.Close(); err == nil {
+		err = closeErr
+	}
+	if errors.Is(err, net.ErrClosed) {
+		err = nil
+	}
+	return sentBytes.Load(), receivedBytes.Load(), err
+}
+
+// closeWrite closes the sending side of conn, or conn entirely if it
+// can't be half closed
+func closeWrite(conn net.Conn) error {
+	if c, ok := conn.(interface{ CloseWrite() error }); ok {
+		return c.CloseWrite()
+	}
+	return conn.Close()
+}
+
+// countingWriter counts the bytes written to a writer
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Int64
+}
+
+// Write implements io.Writer
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package proxier.
+package proxier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	spdystream "k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+)
+
+// refusedPort is a port the fake kubelet reports an error for
+const refusedPort = "9999"
+
+// newFakeKubelet starts a server that implements the port-forward
+// protocol over SPDY, echoing everything sent to a port
+func newFakeKubelet(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, err := httpstream.Handshake(req, w, []string{portforward.PortForwardProtocolV1Name}); err != nil {
+			return
+		}
+
+		conn := spdystream.NewResponseUpgrader().UpgradeResponse(w, req,
+			func(stream httpstream.Stream, replySent <-chan struct{}) error {
+				go func() {
+					<-replySent
+					defer stream.Close()
+
+					refused := stream.Headers().Get(corev1.PortHeader) == refusedPort
+					switch stream.Headers().Get(corev1.StreamType) {
+					case corev1.StreamTypeError:
+						if refused {
+							fmt.Fprint(stream, "connection refused")
+						}
+					case corev1.StreamTypeData:
+						if !refused {
+							io.Copy(stream, stream) //nolint:errcheck // Why: the client checks what's echoed
+						}
+					}
+				}()
+				return nil
+			})
+		if conn != nil {
+			<-conn.CloseChan()
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestDialer creates a podDialer for the server at rawURL
+func newTestDialer(t *testing.T, rawURL string, transport kube.PortForwardTransport, onFailure func(error)) *podDialer {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dialer, err := kube.NewPortForwardDialer(&rest.Config{Host: rawURL}, u, transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := newPodDialer(logrus.New(), dialer, onFailure)
+	t.Cleanup(d.Close)
+	return d
+}
+
+// roundTrip sends msg to addr and returns everything sent back, a
+// connection that was closed early returns what was received so far
+func roundTrip(t *testing.T, addr, msg string) string {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second)) //nolint:errcheck // Why: only guards against hangs
+
+	if _, err := io.WriteString(conn, msg); err != nil {
+		t.Fatal(err)
+	}
+	conn.(*net.TCPConn).CloseWrite() //nolint:errcheck // Why: the echo ends with what was sent
+
+	b, err := io.ReadAll(conn)
+	if err != nil && !errors.Is(err, syscall.ECONNRESET) {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestForwarder(t *testing.T) {
+	// the fake kubelet only supports SPDY, so websocket falls back to it
+	for _, transport := range []kube.PortForwardTransport{kube.PortForwardTransportSPDY, kube.PortForwardTransportWebSocket} {
+		t.Run(string(transport), func(t *testing.T) {
+			testForwarder(t, transport)
+		})
+	}
+}
+
+func testForwarder(t *testing.T, transport kube.PortForwardTransport) {
+	srv := newFakeKubelet(t)
+	d := newTestDialer(t, srv.URL, transport, func(err error) { t.Errorf("unexpected port-forward failure: %v", err) })
+
+	rejected := ConnHook(func(info *ConnInfo, conn net.Conn) (net.Conn, error) {
+		if info.Port.Port == 81 {
+			return nil, fmt.Errorf("rejected")
+		}
+		return conn, nil
+	})
+
+	ports := []ForwardedPort{
+		{Port: 80, TargetPort: 8080},
+		{Port: 81, TargetPort: 8080},
+		{Port: 82, TargetPort: 9999},
+	}
+	f, err := newForwarder(context.Background(), logrus.New(), ConnInfo{}, []string{"127.0.0.1"}, ports, d.Dial,
+		[]ConnHook{rejected})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// connections are sent to the pod, over the same port-forward
+	for range 3 {
+		if got := roundTrip(t, f.listeners[0].Addr().String(), "ping"); got != "ping" {
+			t.Errorf("forwarder echoed %q, expected ping", got)
+		}
+	}
+
+	if got := roundTrip(t, f.listeners[1].Addr().String(), "ping"); got != "" {
+		t.Errorf("forwarder sent %q for a rejected connection", got)
+	}
+
+	// an error forwarding closes the connection, and the port-forward is
+	// recreated for the next one
+	if got := roundTrip(t, f.listeners[2].Addr().String(), "ping"); got != "" {
+		t.Errorf("forwarder sent %q for a refused connection", got)
+	}
+	if got := roundTrip(t, f.listeners[0].Addr().String(), "ping"); got != "ping" {
+		t.Errorf("forwarder echoed %q after an error, expected ping", got)
+	}
+}
+
+func TestPodDialer_Failure(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	failures := 0
+	d := newTestDialer(t, srv.URL, kube.PortForwardTransportWebSocket, func(error) { failures++ })
+	for range 2 {
+		if _, err := d.Dial(context.Background(), &ForwardedPort{TargetPort: 8080}); err == nil {
+			t.Fatal("Dial() to a missing pod didn't fail")
+		}
+	}
+	if failures != 1 {
+		t.Errorf("onFailure was called %d times, expected 1", failures)
+	}
+}
+
+// blockingDialer is a httpstream.Dialer whose dials block until release
+// is closed, and then fail
+type blockingDialer struct {
+	release chan struct{}
+	dials   atomic.Int32
+}
+
+// Dial implements httpstream.Dialer
+func (d *blockingDialer) Dial(...string) (httpstream.Connection, string, error) {
+	d.dials.Add(1)
+	<-d.release
+	return nil, "", errors.New("pod is gone")
+}
+
+func TestPodDialer_SlowDial(t *testing.T) {
+	dialer := &blockingDialer{release: make(chan struct{})}
+	d := newPodDialer(logrus.New(), dialer, func(error) {})
+
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := d.Dial(context.Background(), &ForwardedPort{TargetPort: 8080})
+			errs <- err
+		}()
+	}
+
+	// waiting for the port-forward doesn't block a canceled connection,
+	// or closing the dialer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.Dial(ctx, &ForwardedPort{TargetPort: 8080}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Dial() with a canceled context returned %v", err)
+	}
+	d.Close()
+
+	close(dialer.release)
+	for range 2 {
+		if err := <-errs; err == nil {
+			t.Error("Dial() didn't fail")
+		}
+	}
+	if got := dialer.dials.Load(); got != 1 {
+		t.Errorf("port-forward was created %d times, expected 1", got)
+	}
+}
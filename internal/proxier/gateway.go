@@ -0,0 +1,264 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the gateway, a SOCKS5 and HTTP CONNECT proxy to the cluster.
+package proxier
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/kevents"
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+// handshakeTimeout is how long a client has to send its request
+const handshakeTimeout = 30 * time.Second
+
+// Errors returned when a proxy request can't be connected, which are
+// reported to the client
+var (
+	// errNotFound is returned when a host isn't a service or pod
+	errNotFound = errors.New("host not found in cluster")
+
+	// errNoEndpoints is returned when a service has no ready pods
+	errNoEndpoints = errors.New("no ready endpoints")
+
+	// errRejected is returned when a ConnHook rejected the connection
+	errRejected = errors.New("connection rejected")
+)
+
+// proxyRequest is a request, read from a client, to connect to host:port
+type proxyRequest struct {
+	host string
+	port uint16
+
+	// reply tells the client if the connection was established, err is
+	// nil when it was
+	reply func(err error) error
+}
+
+// address returns the host:port the client wants to connect to
+func (r *proxyRequest) address() string {
+	return net.JoinHostPort(r.host, strconv.FormatUint(uint64(r.port), 10))
+}
+
+// Gateway is a SOCKS5 and HTTP CONNECT proxy to the cluster. Hosts are
+// resolved to a pod through the informer cache, and connections are sent
+// to it over a port-forward that's created on demand. Unlike the
+// Proxier, no addresses are allocated and the hosts file isn't modified,
+// so it doesn't require root.
+type Gateway struct {
+	k    kubernetes.Interface
+	rest *rest.Config
+	log  logrus.FieldLogger
+	opts *ProxyOpts
+
+	hooks []ConnHook
+
+	services  corelisters.ServiceLister
+	endpoints corelisters.EndpointsLister
+	pods      corelisters.PodLister
+
+	// newDialer creates the port-forward dialer of a pod
+	newDialer func(pod *PodInfo) (httpstream.Dialer, error)
+
+	// dialers are the port-forwards to pods, which are shared by every
+	// connection to the same pod
+	mu      sync.Mutex
+	dialers map[string]*podDialer
+	closed  bool
+}
+
+// NewGateway creates a new gateway, it doesn't accept connections until
+// Serve is called. Only the ClusterDomain, Capture, Hooks and
+// PortForwardTransport options are used.
+func NewGateway(k kubernetes.Interface, kconf *rest.Config, log logrus.FieldLogger, opts *ProxyOpts) *Gateway {
+	g := &Gateway{
+		k:         k,
+		rest:      kconf,
+		log:       log.WithField("component", "gateway"),
+		opts:      opts,
+		hooks:     opts.hooks(),
+		services:  kevents.GlobalCache.Core().V1().Services().Lister(),
+		endpoints: kevents.GlobalCache.Core().V1().Endpoints().Lister(),
+		pods:      kevents.GlobalCache.Core().V1().Pods().Lister(),
+		dialers:   make(map[string]*podDialer),
+	}
+	g.newDialer = func(pod *PodInfo) (httpstream.Dialer, error) {
+		return kube.NewPortForwardDialer(g.rest, portForwardURL(g.k, pod), g.opts.PortForwardTransport)
+	}
+	return g
+}
+
+// Serve accepts proxy connections on l until the context is canceled.
+// The port-forwards created for them are closed before it returns.
+func (g *Gateway) Serve(ctx context.Context, l net.Listener) error {
+	defer g.close()
+
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "failed to accept connection")
+		}
+
+		go g.handle(ctx, conn)
+	}
+}
+
+// handle reads the request of a client, SOCKS5 or HTTP CONNECT, and sends
+// the connection to the pod it resolves to
+func (g *Gateway) handle(ctx context.Context, conn net.Conn) { //nolint:funlen // Why: it's a sequence of steps
+	log := g.log.WithField("client", conn.RemoteAddr().String())
+	start := time.Now()
+
+	conn.SetDeadline(time.Now().Add(handshakeTimeout)) //nolint:errcheck // Why: only guards against stuck clients
+
+	// SOCKS5 requests start with their version, HTTP ones with a method
+	r := bufio.NewReader(conn)
+	version, err := r.Peek(1)
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	var req *proxyRequest
+	if version[0] == socks5Version {
+		req, err = readSOCKS5Request(r, conn)
+	} else {
+		req, err = readConnectRequest(r, conn)
+	}
+	if err != nil {
+		log.WithError(err).Debug("invalid proxy request")
+		conn.Close()
+		return
+	}
+	log = log.WithField("target", req.address())
+
+	info, err := g.resolve(req.host, uint(req.port))
+	if err != nil {
+		log.WithError(err).Debug("failed to resolve target")
+		req.reply(err) //nolint:errcheck // Why: the connection is closed anyways
+		conn.Close()
+		return
+	}
+	log = log.WithField("endpoint", info.Pod.Key())
+
+	// anything the client sent after its request was read into r
+	client := net.Conn(&bufferedConn{Conn: conn, r: r})
+	for _, hook := range g.hooks {
+		hooked, err := hook(info, client)
+		if err != nil {
+			log.WithError(err).Debug("rejected connection")
+			req.reply(errRejected) //nolint:errcheck // Why: the connection is closed anyways
+			conn.Close()
+			return
+		}
+		client = hooked
+	}
+	defer client.Close()
+
+	remote, err := g.dial(ctx, info)
+	if err != nil {
+		log.WithError(err).Warn("failed to forward connection")
+		req.reply(err) //nolint:errcheck // Why: the connection is closed anyways
+		return
+	}
+
+	if err := req.reply(nil); err != nil {
+		remote.Close()
+		return
+	}
+	conn.SetDeadline(time.Time{}) //nolint:errcheck // Why: it was set on the same conn
+
+	sent, received, err := pipe(client, remote)
+	log = log.WithFields(logrus.Fields{"sent": sent, "received": received, "duration": time.Since(start).String()})
+	if err != nil {
+		log.WithError(err).Warn("proxy connection failed")
+		return
+	}
+	log.Debug("proxy connection closed")
+}
+
+// dial opens a connection to the pod and port of info, over the pod's
+// port-forward
+func (g *Gateway) dial(ctx context.Context, info *ConnInfo) (net.Conn, error) {
+	key := info.Pod.Key()
+
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil, fmt.Errorf("gateway was closed")
+	}
+
+	d, ok := g.dialers[key]
+	if !ok {
+		dialer, err := g.newDialer(&info.Pod)
+		if err != nil {
+			g.mu.Unlock()
+			return nil, errors.Wrap(err, "failed to create port-forward dialer")
+		}
+
+		// if the port-forward can't be created, e.g. because the pod is
+		// gone, the next connection to the pod creates a new one
+		var pd *podDialer
+		pd = newPodDialer(g.log.WithField("endpoint", key), dialer, func(error) {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			if g.dialers[key] == pd {
+				delete(g.dialers, key)
+			}
+		})
+		g.dialers[key] = pd
+		d = pd
+	}
+	g.mu.Unlock()
+
+	return d.Dial(ctx, &info.Port)
+}
+
+// close closes every port-forward, and stops creating new ones
+func (g *Gateway) close() {
+	g.mu.Lock()
+	g.closed = true
+	dialers := g.dialers
+	g.dialers = make(map[string]*podDialer)
+	g.mu.Unlock()
+
+	// dialers lock themselves, and call back into the gateway when they
+	// fail, so they're closed without holding the lock
+	for _, d := range dialers {
+		d.Close()
+	}
+}
+
+// bufferedConn is a net.Conn that reads from r instead of the conn
+type bufferedConn struct {
+	net.Conn
+
+	r io.Reader
+}
+
+// Read implements io.Reader
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,251 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package proxier.
+package proxier
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/kevents"
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/proxy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+// newTestGateway creates a gateway for a cluster with a service, a
+// headless service and a service without endpoints
+func newTestGateway(t *testing.T) *Gateway {
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: "default", Name: name}
+	}
+	address := func(pod, ip string) corev1.EndpointAddress {
+		return corev1.EndpointAddress{IP: ip, Hostname: pod, TargetRef: &corev1.ObjectReference{Kind: PodKind, Name: pod}}
+	}
+
+	k := fake.NewClientset(
+		&corev1.Service{
+			ObjectMeta: meta("web"),
+			Spec: corev1.ServiceSpec{
+				ClusterIP:  "10.96.0.10",
+				ClusterIPs: []string{"10.96.0.10"},
+				Ports:      []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}},
+			},
+		},
+		&corev1.Endpoints{
+			ObjectMeta: meta("web"),
+			Subsets: []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{address("web-0", "10.244.0.5")},
+				Ports:     []corev1.EndpointPort{{Name: "http", Port: 8080}},
+			}},
+		},
+		&corev1.Service{
+			ObjectMeta: meta("db"),
+			Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone},
+		},
+		&corev1.Endpoints{
+			ObjectMeta: meta("db"),
+			Subsets: []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{address("db-0", "10.244.0.6")},
+			}},
+		},
+		&corev1.Service{
+			ObjectMeta: meta("empty"),
+			Spec: corev1.ServiceSpec{
+				ClusterIP:  "10.96.0.11",
+				ClusterIPs: []string{"10.96.0.11"},
+				Ports:      []corev1.ServicePort{{Port: 80}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: meta("web-0"),
+			Status: corev1.PodStatus{
+				Phase:  corev1.PodRunning,
+				PodIP:  "10.244.0.5",
+				PodIPs: []corev1.PodIP{{IP: "10.244.0.5"}},
+			},
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	kevents.ConfigureGlobalCache(k, "")
+	g := NewGateway(k, &rest.Config{}, logrus.New(), &ProxyOpts{ClusterDomain: "cluster.local"})
+	kevents.GlobalCache.Start(ctx.Done())
+	kevents.GlobalCache.WaitForCacheSync(ctx.Done())
+	return g
+}
+
+func TestGateway_Resolve(t *testing.T) {
+	g := newTestGateway(t)
+
+	web := &ConnInfo{
+		Service: ServiceInfo{Name: "web", Namespace: "default"},
+		Pod:     PodInfo{Name: "web-0", Namespace: "default"},
+		Port:    ForwardedPort{Name: "http", Port: 80, TargetPort: 8080, LocalPort: 80},
+	}
+	db := &ConnInfo{
+		Service: ServiceInfo{Name: "db", Namespace: "default"},
+		Pod:     PodInfo{Name: "db-0", Namespace: "default"},
+		Port:    ForwardedPort{Port: 5432, TargetPort: 5432, LocalPort: 5432},
+	}
+
+	tests := []struct {
+		host    string
+		port    uint
+		want    *ConnInfo
+		wantErr error
+	}{
+		{host: "web", port: 80, want: web},
+		{host: "web.default", port: 80, want: web},
+		{host: "web.default.svc", port: 80, want: web},
+		{host: "WEB.default.svc.cluster.local.", port: 80, want: web},
+		{host: "10.96.0.10", port: 80, want: web},
+		{host: "web.default", port: 8080, wantErr: errNotFound},
+		{host: "db.default.svc.cluster.local", port: 5432, want: db},
+		{host: "db-0.db.default.svc.cluster.local", port: 5432, want: db},
+		{host: "db-1.db.default", port: 5432, wantErr: errNotFound},
+		{host: "empty.default", port: 80, wantErr: errNoEndpoints},
+		{host: "10.96.0.11", port: 80, wantErr: errNoEndpoints},
+		{
+			host: "10.244.0.5",
+			port: 8080,
+			want: &ConnInfo{
+				Pod:  PodInfo{Name: "web-0", Namespace: "default"},
+				Port: ForwardedPort{Port: 8080, TargetPort: 8080, LocalPort: 8080},
+			},
+		},
+		{host: "10.0.0.1", port: 80, wantErr: errNotFound},
+		{host: "example.com", port: 443, wantErr: errNotFound},
+		{host: "a.b.c.d.e", port: 443, wantErr: errNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s:%d", tt.host, tt.port), func(t *testing.T) {
+			got, err := g.resolve(tt.host, tt.port)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolve() error = %v, expected %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("resolve() returned unexpected info (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGateway(t *testing.T) {
+	g := newTestGateway(t)
+
+	srv := newFakeKubelet(t)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.newDialer = func(*PodInfo) (httpstream.Dialer, error) {
+		return kube.NewPortForwardDialer(&rest.Config{Host: srv.URL}, u, kube.PortForwardTransportSPDY)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() { errChan <- g.Serve(ctx, l) }()
+
+	t.Run("socks5", func(t *testing.T) {
+		dialer, err := proxy.SOCKS5("tcp", l.Addr().String(), nil, proxy.Direct)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conn, err := dialer.Dial("tcp", "web.default.svc.cluster.local:80")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := echo(t, conn, conn, "ping"); got != "ping" {
+			t.Errorf("gateway echoed %q, expected ping", got)
+		}
+
+		if _, err := dialer.Dial("tcp", "missing.default:80"); err == nil {
+			t.Error("expected dialing a missing service to fail")
+		}
+	})
+
+	t.Run("connect", func(t *testing.T) {
+		conn, r, resp := connect(t, l.Addr().String(), "10.96.0.10:80")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("CONNECT returned %s, expected 200", resp.Status)
+		}
+		if got := echo(t, conn, r, "ping"); got != "ping" {
+			t.Errorf("gateway echoed %q, expected ping", got)
+		}
+
+		conn, _, resp = connect(t, l.Addr().String(), "empty.default:80")
+		conn.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("CONNECT to a service without endpoints returned %s, expected 503", resp.Status)
+		}
+	})
+
+	cancel()
+	if err := <-errChan; err != nil {
+		t.Errorf("expected Serve to return nil when canceled, got %v", err)
+	}
+}
+
+// connect sends a CONNECT request for target to the gateway at addr,
+// returning the connection, the reader to read the rest of it from, and
+// the gateway's response
+func connect(t *testing.T, addr, target string) (net.Conn, io.Reader, *http.Response) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second)) //nolint:errcheck // Why: only guards against hangs
+
+	req := &http.Request{Method: http.MethodConnect, URL: &url.URL{Opaque: target}, Host: target, Header: http.Header{}}
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, r, resp
+}
+
+// echo sends msg over conn, and returns everything sent back, which is
+// read from r
+func echo(t *testing.T, conn net.Conn, r io.Reader, msg string) string {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second)) //nolint:errcheck // Why: only guards against hangs
+
+	if _, err := io.WriteString(conn, msg); err != nil {
+		t.Fatal(err)
+	}
+	closeWrite(conn) //nolint:errcheck // Why: the echo ends with what was sent
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
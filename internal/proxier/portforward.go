@@ -19,25 +19,18 @@ package proxier
 import (
 	"context"
 	"fmt"
-	"net"
-	"net/http"
 	"net/netip"
-	"os"
-	"os/exec"
-	"runtime"
+	"net/url"
 	"sync"
 	"time"
 
-	"github.com/egymgmbh/go-prefix-writer/prefixer"
-	"github.com/fatih/color"
+	"github.com/getoutreach/localizer/internal/kube"
 	"github.com/getoutreach/localizer/pkg/hostsfile"
 	"github.com/metal-stack/go-ipam"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/portforward"
-	"k8s.io/client-go/transport/spdy"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -52,21 +45,19 @@ var (
 	ErrAlreadyExists = errors.New("already have a port-forward for this service")
 )
 
-// bold makes the provided text bold.
-var bold = color.New(color.Bold).SprintFunc()
-
-// red makes the provided text red.
-var red = color.New(color.FgRed).SprintFunc()
-
 type worker struct {
 	k    kubernetes.Interface
 	rest *rest.Config
 	log  logrus.FieldLogger
 
 	ippool ipam.Ipamer
-	ipCidr string
 	dns    *hostsfile.File
 
+	// ipCidr and ipv6Cidr are the prefixes we allocate addresses from
+	// for each port-forward. Either may be empty, but not both.
+	ipCidr   string
+	ipv6Cidr string
+
 	reqChan  chan PortForwardRequest
 	doneChan chan<- struct{}
 
@@ -79,6 +70,16 @@ type worker struct {
 	// access.
 	lastTouchTime time.Time
 	touchMu       sync.Mutex
+
+	// hooks are called for every connection accepted by a port-forward
+	hooks []ConnHook
+
+	// transport is the protocol port-forwards are tunneled over
+	transport kube.PortForwardTransport
+
+	// direct is true if services are routed to directly, see
+	// ProxyOpts.Direct
+	direct bool
 }
 
 // NewPortForwarder creates a new port-forward worker that handles
@@ -89,22 +90,18 @@ func NewPortForwarder(ctx context.Context, k kubernetes.Interface,
 	r *rest.Config, log logrus.FieldLogger, opts *ProxyOpts) (chan<- PortForwardRequest, <-chan struct{}, *worker, error) {
 	ipamInstance := ipam.New(ctx)
 
-	_, cidr, err := net.ParseCIDR(opts.IPCidr)
-	if err != nil {
-		return nil, nil, nil, errors.Wrap(err, "failed to parse provided cidr")
+	if !opts.Direct && opts.IPCidr == "" && opts.IPv6Cidr == "" {
+		return nil, nil, nil, fmt.Errorf("at least one of an IPv4 or IPv6 cidr must be provided")
 	}
 
-	prefix, err := ipamInstance.NewPrefix(ctx, opts.IPCidr)
+	ipCidr, err := newIPPool(ctx, ipamInstance, opts.IPCidr, false)
 	if err != nil {
-		return nil, nil, nil, errors.Wrap(err, "failed to create ip pool")
+		return nil, nil, nil, err
 	}
 
-	defaultIP := "127.0.0.1"
-	if cidr.Contains(net.ParseIP(defaultIP)) {
-		_, err = ipamInstance.AcquireSpecificIP(ctx, prefix.Cidr, defaultIP)
-		if err != nil {
-			return nil, nil, nil, errors.Wrap(err, "failed to create ip pool")
-		}
+	ipv6Cidr, err := newIPPool(ctx, ipamInstance, opts.IPv6Cidr, true)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	hosts, err := hostsfile.New("", "")
@@ -120,12 +117,16 @@ func NewPortForwarder(ctx context.Context, k kubernetes.Interface,
 		rest:          r,
 		log:           log,
 		ippool:        ipamInstance,
-		ipCidr:        prefix.Cidr,
+		ipCidr:        ipCidr,
+		ipv6Cidr:      ipv6Cidr,
 		dns:           hosts,
 		reqChan:       reqChan,
 		doneChan:      doneChan,
 		portForwards:  make(map[string]*PortForwardConnection),
 		lastTouchTime: time.Now(),
+		hooks:         opts.hooks(),
+		transport:     opts.PortForwardTransport,
+		direct:        opts.Direct,
 	}
 
 	go w.Start(ctx)
@@ -133,6 +134,76 @@ func NewPortForwarder(ctx context.Context, k kubernetes.Interface,
 	return reqChan, doneChan, w, nil
 }
 
+// newIPPool creates a prefix in the provided ipam instance for the given
+// cidr, returning the cidr of the prefix. The loopback address of the
+// cidr's family is reserved, if it falls inside of the cidr, since
+// it's commonly used by other local processes. If cidr is empty, then
+// no prefix is created and an empty string is returned.
+func newIPPool(ctx context.Context, ipamInstance ipam.Ipamer, cidr string, ipv6 bool) (string, error) {
+	if cidr == "" {
+		return "", nil
+	}
+
+	parsedCidr, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse provided cidr %q", cidr)
+	}
+
+	if parsedCidr.Addr().Is6() != ipv6 {
+		family := "IPv4"
+		if ipv6 {
+			family = "IPv6"
+		}
+		return "", fmt.Errorf("provided cidr %q is not an %s cidr", cidr, family)
+	}
+
+	prefix, err := ipamInstance.NewPrefix(ctx, cidr)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create ip pool")
+	}
+
+	defaultIP := loopbackAddress(ipv6)
+	if parsedCidr.Contains(defaultIP) {
+		_, err = ipamInstance.AcquireSpecificIP(ctx, prefix.Cidr, defaultIP.String())
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create ip pool")
+		}
+	}
+
+	return prefix.Cidr, nil
+}
+
+// acquireIP allocates an IP address for a port-forward from the
+// provided cidr. If port-forwards have to share the loopback address
+// on this platform, then the loopback address is returned instead.
+func (w *worker) acquireIP(ctx context.Context, cidr string) (netip.Addr, error) {
+	ipv6 := cidr == w.ipv6Cidr
+	if sharesLoopbackAddress(ipv6) {
+		return loopbackAddress(ipv6), nil
+	}
+
+	ipAddress, err := w.ippool.AcquireIP(ctx, cidr)
+	if err != nil {
+		return netip.Addr{}, errors.Wrap(err, "failed to allocate IP")
+	}
+
+	if err := addLoopbackAlias(ipAddress.IP); err != nil {
+		// We still own the IP, so release it since we're not using it.
+		if rerr := w.ippool.ReleaseIPFromPrefix(ctx, cidr, ipAddress.IP.String()); rerr != nil {
+			w.log.WithError(rerr).Warn("failed to release ip address")
+		}
+		return netip.Addr{}, err
+	}
+
+	return ipAddress.IP, nil
+}
+
+// isSharedAddress returns true if the provided address is shared
+// between port-forwards, see sharesLoopbackAddress.
+func isSharedAddress(ip netip.Addr) bool {
+	return sharesLoopbackAddress(ip.Is6()) && ip == loopbackAddress(ip.Is6())
+}
+
 // shutdown shuts down the worker by stopping all port-forwards.
 func (w *worker) shutdown() {
 	// create a temporary context for shutting down
@@ -265,6 +336,10 @@ func (w *worker) CreatePortForward(ctx context.Context, req *CreatePortForwardRe
 		}
 	}
 
+	if w.direct {
+		return w.createDirect(ctx, req)
+	}
+
 	pf := &PortForwardConnection{
 		Service: req.Service,
 		Status:  PortForwardStatusRunning,
@@ -281,27 +356,52 @@ func (w *worker) CreatePortForward(ctx context.Context, req *CreatePortForwardRe
 		}
 	}()
 
-	// TODO(jaredallard): need to release on error
-	ipAddress, err := w.ippool.AcquireIP(ctx, w.ipCidr)
-	if err != nil {
-		return errors.Wrap(err, "failed to allocate IP")
-	}
-	pf.IP = ipAddress.IP
+	listenAddresses := make([]string, 0, 2)
+	for _, cidr := range []string{w.ipCidr, w.ipv6Cidr} {
+		if cidr == "" {
+			continue
+		}
+
+		ip, err := w.acquireIP(ctx, cidr)
+		if err != nil {
+			return err
+		}
 
-	// We only need to create alias on darwin, on other platforms
-	// lo0 becomes lo and routes the full /8
-	if runtime.GOOS == "darwin" && os.Getenv("DISABLE_LOOPBACK_ALIAS") == "" {
-		args := []string{"lo0", "alias", ipAddress.IP.String(), "up"}
-		//nolint:govet // Why: We're OK shadowing err
-		if err := exec.Command("ifconfig", args...).Run(); err != nil {
-			return errors.Wrap(err, "failed to create ip link")
+		if ip.Is6() {
+			pf.IPv6 = ip
+		} else {
+			pf.IP = ip
 		}
+		pf.Hostnames = req.Hostnames
+
+		if isSharedAddress(ip) {
+			//nolint:govet // Why: We're OK shadowing err
+			if err := w.dns.AppendHosts(ip.String(), req.Hostnames); err != nil {
+				return errors.Wrap(err, "failed to add host entry")
+			}
+		} else {
+			//nolint:govet // Why: We're OK shadowing err
+			if err := w.dns.AddHosts(ip.String(), req.Hostnames); err != nil {
+				return errors.Wrap(err, "failed to add host entry")
+			}
+		}
+
+		listenAddresses = append(listenAddresses, ip.String())
 	}
-	pf.Hostnames = req.Hostnames
 
-	//nolint:govet // Why: We're OK shadowing err
-	if err := w.dns.AddHosts(ipAddress.IP.String(), req.Hostnames); err != nil {
-		return errors.Wrap(err, "failed to add host entry")
+	// Ensure that the ports we're going to listen on are free, remapping
+	// them to another port if they aren't. This is mostly hit when
+	// port-forwards share an address.
+	ports, err := allocatePorts(listenAddresses, req.Ports)
+	if err != nil {
+		return errors.Wrap(err, "failed to allocate local ports")
+	}
+	pf.Ports = ports
+
+	for i := range ports {
+		if ports[i].IsRemapped() {
+			log.Warnf("port %d is already in use locally, remapped to %d", ports[i].Port, ports[i].LocalPort)
+		}
 	}
 
 	//nolint:govet // Why: We're OK shadowing err
@@ -309,11 +409,6 @@ func (w *worker) CreatePortForward(ctx context.Context, req *CreatePortForwardRe
 		return errors.Wrap(err, "failed to save host changes")
 	}
 
-	transport, upgrader, err := spdy.RoundTripperFor(w.rest)
-	if err != nil {
-		return errors.Wrap(err, "failed to upgrade connection")
-	}
-
 	var pod *PodInfo
 	if req.Endpoint == nil {
 		podInfo, err := w.getPodForService(ctx, &req.Service)
@@ -331,46 +426,21 @@ func (w *worker) CreatePortForward(ctx context.Context, req *CreatePortForwardRe
 		pf.Pod = *pod
 
 		log.Info("creating tunnel")
-		dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", w.k.CoreV1().RESTClient().Post().
-			Resource("pods").
-			Namespace(pod.Namespace).
-			Name(pod.Name).
-			SubResource("portforward").URL())
-
-		fw, err := portforward.NewOnAddresses(dialer,
-			// Listen information.
-			[]string{ipAddress.IP.String()}, req.Ports,
-
-			// signal channels. We don't pass a stop channel because we handle
-			// calling Close() on context cancel. We don't use a ready channel
-			// because we don't wait for the tunnel to be ready before continuing.
-			nil, nil,
-
-			// Forward port-forwarder output. We don't write stdout because it
-			// contains information we already log (like "forwarding port").
-			// We do write to stderr because it can contain error information
-			// that's useful for debugging why a port-forward is having issues
-			// (or if it failed to start).
-			nil,
-			prefixer.New(os.Stderr, func() string { return red(bold(pod.Key() + " (port-forward) stderr: ")) }),
-		)
+		dialer, err := kube.NewPortForwardDialer(w.rest, portForwardURL(w.k, pod), w.transport)
 		if err != nil {
-			return errors.Wrap(err, "failed to create port-forward")
+			return errors.Wrap(err, "failed to create port-forward dialer")
 		}
-		pf.pf = fw
-
-		// Spin up a goroutine to forward the ports and trigger a recreate
-		// if it dies.
-		go func() {
-			err := fw.ForwardPorts()
 
-			// if the context was canceled, ignore the error and don't attempt
-			// to recreate the tunnel.
+		// Connections are accepted by the forwarder, which opens a stream
+		// to the pod for each of them. If the port-forward can't be
+		// created, e.g. because the pod is gone, the tunnel is recreated.
+		pd := newPodDialer(log, dialer, func(err error) {
+			// if the context was canceled, don't attempt to recreate the
+			// tunnel.
 			if ctx.Err() != nil {
 				return
 			}
 
-			// otherwise, recreate it
 			log.Debugf("port-forward failed, recreating tunnel: %v", err)
 			w.reqChan <- PortForwardRequest{
 				CreatePortForwardRequest: &CreatePortForwardRequest{
@@ -381,7 +451,14 @@ func (w *worker) CreatePortForward(ctx context.Context, req *CreatePortForwardRe
 					RecreateReason: err.Error(),
 				},
 			}
-		}()
+		})
+		pf.dialer = pd
+
+		fwd, err := newForwarder(ctx, log, ConnInfo{Service: req.Service, Pod: *pod}, listenAddresses, ports, pd.Dial, w.hooks)
+		if err != nil {
+			return err
+		}
+		pf.fwd = fwd
 	} else {
 		log.Warn("skipping tunnel creation due to no endpoint being found")
 		pf.Status = PortForwardStatusWaiting
@@ -397,6 +474,53 @@ func (w *worker) CreatePortForward(ctx context.Context, req *CreatePortForwardRe
 	return nil
 }
 
+// createDirect points the hostnames of a service at its ClusterIPs, which
+// are routed to the cluster by the VPN, instead of port-forwarding it.
+// The VPN only routes IPv4, so IPv6 ClusterIPs are ignored.
+func (w *worker) createDirect(ctx context.Context, req *CreatePortForwardRequest) error {
+	pf := &PortForwardConnection{
+		Service:   req.Service,
+		Status:    PortForwardStatusRunning,
+		Ports:     req.Ports,
+		Hostnames: req.Hostnames,
+		direct:    true,
+	}
+	w.portForwards[req.Service.Key()] = pf
+
+	if len(req.ClusterIPs) == 0 {
+		pf.Status = PortForwardStatusWaiting
+		pf.StatusReason = "Headless services can't be routed to directly."
+		return nil
+	}
+
+	for _, ip := range req.ClusterIPs {
+		if ip.Is4() {
+			pf.IP = ip
+			break
+		}
+	}
+	if !pf.IP.IsValid() {
+		pf.Status = PortForwardStatusWaiting
+		pf.StatusReason = "IPv6-only services can't be routed to directly."
+		return nil
+	}
+
+	if err := w.dns.AddHosts(pf.IP.String(), req.Hostnames); err != nil {
+		return errors.Wrap(err, "failed to add host entry")
+	}
+
+	return errors.Wrap(w.dns.Save(ctx), "failed to save host changes")
+}
+
+// portForwardURL returns the URL of the portforward subresource of pod
+func portForwardURL(k kubernetes.Interface, pod *PodInfo) *url.URL {
+	return k.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").URL()
+}
+
 func (w *worker) setPortForwardConnectionStatus(_ context.Context, si ServiceInfo, status PortForwardStatus, reason string) {
 	key := si.Key()
 	pf, ok := w.portForwards[key]
@@ -410,42 +534,70 @@ func (w *worker) setPortForwardConnectionStatus(_ context.Context, si ServiceInf
 }
 
 func (w *worker) stopPortForward(ctx context.Context, conn *PortForwardConnection) error {
-	if conn.pf != nil {
-		conn.pf.Close()
+	if conn.fwd != nil {
+		conn.fwd.Close()
+	}
+	if conn.dialer != nil {
+		conn.dialer.Close()
 	}
 
 	errs := make([]error, 0)
-	if conn.IP.IsValid() {
+	released := false
+	for _, ip := range []*netip.Addr{&conn.IP, &conn.IPv6} {
+		if !ip.IsValid() {
+			continue
+		}
+		released = true
+
+		// ClusterIPs aren't ours either, so only their hosts need to be
+		// removed
+		if conn.direct {
+			if err := w.dns.RemoveAddress(ip.String()); err != nil {
+				errs = append(errs, errors.Wrap(err, "failed to remove ip address from hostsfile"))
+			}
+
+			*ip = netip.Addr{}
+			continue
+		}
+
+		// Shared addresses are never allocated, so only our hosts need
+		// to be removed.
+		if isSharedAddress(*ip) {
+			if err := w.dns.RemoveHosts(ip.String(), conn.Hostnames); err != nil {
+				errs = append(errs, errors.Wrap(err, "failed to remove hosts from hostsfile"))
+			}
+
+			*ip = netip.Addr{}
+			continue
+		}
+
 		// If we are on a platform that needs aliases
 		// then we need to remove it
-		if runtime.GOOS == "darwin" && os.Getenv("DISABLE_LOOPBACK_ALIAS") == "" {
-			ipStr := conn.IP.String()
-			args := []string{"lo0", "-alias", ipStr}
-			if err := exec.Command("ifconfig", args...).Run(); err != nil {
-				message := ""
-				var exitError *exec.ExitError
-				if ok := errors.As(err, &exitError); ok {
-					message = string(exitError.Stderr)
-				}
-				errs = append(errs, errors.Wrapf(err, "failed to release ip alias: %s", message))
-			}
+		if err := removeLoopbackAlias(*ip); err != nil {
+			errs = append(errs, err)
 		}
 
-		err := w.ippool.ReleaseIPFromPrefix(ctx, w.ipCidr, conn.IP.String())
-		if err != nil {
+		cidr := w.ipCidr
+		if ip.Is6() {
+			cidr = w.ipv6Cidr
+		}
+
+		if err := w.ippool.ReleaseIPFromPrefix(ctx, cidr, ip.String()); err != nil {
 			errs = append(errs, errors.Wrap(err, "failed to release ip address"))
 		}
 
-		if err := w.dns.RemoveAddress(conn.IP.String()); err != nil {
+		if err := w.dns.RemoveAddress(ip.String()); err != nil {
 			errs = append(errs, errors.Wrap(err, "failed to remove ip address from hostsfile"))
 		}
 
-		// We don't use the context provided because if it's canceled we need to be able to remove it still
+		*ip = netip.Addr{}
+	}
+
+	// We don't use the context provided because if it's canceled we need to be able to remove it still
+	if released {
 		if err := w.dns.Save(ctx); err != nil {
 			errs = append(errs, errors.Wrap(err, "failed to save hosts file after modification(s)"))
 		}
-
-		conn.IP = netip.Addr{}
 	}
 
 	// if we have errors, return them
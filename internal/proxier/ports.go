@@ -0,0 +1,101 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the local port allocator used by port-forwards.
+package proxier
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// maxRemapAttempts is the number of times we'll ask the kernel for a
+// free port before giving up on remapping a port.
+const maxRemapAttempts = 10
+
+// allocatePorts ensures that every port in ports can be listened on
+// for all of the provided addresses. Ports that are already in use
+// on any of the addresses are remapped to a port that is free on all
+// of them. The returned ports are a copy of the provided ports with
+// LocalPort updated.
+//
+// Listeners are held open until every port has been allocated so that
+// two ports can't be remapped to the same local port.
+func allocatePorts(addresses []string, ports []ForwardedPort) ([]ForwardedPort, error) {
+	allocated := make([]ForwardedPort, len(ports))
+	copy(allocated, ports)
+
+	listeners := make([]net.Listener, 0)
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+
+	for i := range allocated {
+		ls, err := listenAll(addresses, allocated[i].LocalPort)
+		if err == nil {
+			listeners = append(listeners, ls...)
+			continue
+		}
+
+		port, ls, err := remapPort(addresses)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to remap port %d", allocated[i].LocalPort)
+		}
+		listeners = append(listeners, ls...)
+		allocated[i].LocalPort = port
+	}
+
+	return allocated, nil
+}
+
+// remapPort finds a port that is free on all of the provided addresses
+// and returns it, as well as the listeners holding it open.
+func remapPort(addresses []string) (uint, []net.Listener, error) {
+	if len(addresses) == 0 {
+		return 0, nil, fmt.Errorf("no addresses to listen on")
+	}
+
+	for range maxRemapAttempts {
+		// Let the kernel pick a free port on the first address and then
+		// ensure it's also free on the rest of them.
+		l, err := net.Listen("tcp", net.JoinHostPort(addresses[0], "0"))
+		if err != nil {
+			return 0, nil, err
+		}
+
+		//nolint:errcheck // Why: We always listen on TCP, which returns a *net.TCPAddr
+		port := uint(l.Addr().(*net.TCPAddr).Port)
+
+		ls, err := listenAll(addresses[1:], port)
+		if err != nil {
+			l.Close()
+			continue
+		}
+
+		return port, append(ls, l), nil
+	}
+
+	return 0, nil, fmt.Errorf("failed to find a free port after %d attempts", maxRemapAttempts)
+}
+
+// listenAll listens on the provided port for every address, returning
+// an error if any of them are unable to be listened on.
+func listenAll(addresses []string, port uint) ([]net.Listener, error) {
+	listeners := make([]net.Listener, 0, len(addresses))
+	for _, addr := range addresses {
+		l, err := net.Listen("tcp", net.JoinHostPort(addr, strconv.FormatUint(uint64(port), 10)))
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+
+	return listeners, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package proxier.
+package proxier
+
+import (
+	"net"
+	"testing"
+)
+
+func TestAllocatePorts(t *testing.T) {
+	// occupy a port so that it has to be remapped
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	//nolint:errcheck // Why: test
+	usedPort := uint(l.Addr().(*net.TCPAddr).Port)
+
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//nolint:errcheck // Why: test
+	freePort := uint(free.Addr().(*net.TCPAddr).Port)
+	free.Close()
+
+	ports, err := allocatePorts([]string{"127.0.0.1"}, []ForwardedPort{
+		{Port: usedPort, TargetPort: 5432, LocalPort: usedPort},
+		{Port: freePort, TargetPort: 8080, LocalPort: freePort},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ports[0].IsRemapped() || ports[0].LocalPort == usedPort {
+		t.Errorf("expected port %d to be remapped, got %d", usedPort, ports[0].LocalPort)
+	}
+
+	if ports[1].IsRemapped() {
+		t.Errorf("expected free port %d to not be remapped, got %d", freePort, ports[1].LocalPort)
+	}
+}
@@ -19,8 +19,10 @@ package proxier
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"time"
 
+	"github.com/getoutreach/localizer/internal/capture"
 	"github.com/getoutreach/localizer/internal/kevents"
 	"github.com/getoutreach/localizer/internal/kube"
 	"github.com/pkg/errors"
@@ -66,20 +68,58 @@ type ServiceStatus struct {
 	// non-running state.
 	Reason string
 
-	// IP is the IP address of this tunnel
+	// IP is the IPv4 address of this tunnel
 	IP string
 
+	// IPv6 is the IPv6 address of this tunnel
+	IPv6 string
+
+	// Hostname is the fully qualified hostname of this service
+	Hostname string
+
 	// Ports are the ports this service is exposing
-	Ports []string
+	Ports []ForwardedPort
 }
 
 type ProxyOpts struct {
 	ClusterDomain string
-	IPCidr        string
+
+	// IPCidr is the IPv4 cidr to allocate tunnel addresses from
+	IPCidr string
+
+	// IPv6Cidr is the IPv6 cidr to allocate tunnel addresses from. When
+	// both IPCidr and IPv6Cidr are set, tunnels are dual-stack.
+	IPv6Cidr string
 
 	// SkipNamespaces is a list of namespaces to skip when forwarding
 	// services.
 	SkipNamespaces []string
+
+	// Capture records the connections of port-forwards, if set
+	Capture *capture.Registry
+
+	// Hooks are called for every connection accepted by a port-forward,
+	// after recording it to Capture
+	Hooks []ConnHook
+
+	// PortForwardTransport is the protocol port-forwards are tunneled
+	// over, defaults to WebSockets
+	PortForwardTransport kube.PortForwardTransport
+
+	// Direct points the hostnames of services at their ClusterIPs instead
+	// of creating port-forwards, for when the cluster is routed to by the
+	// VPN. IPCidr and IPv6Cidr aren't required, and connections aren't
+	// captured or run through Hooks.
+	Direct bool
+}
+
+// hooks returns every hook connections are run through
+func (o *ProxyOpts) hooks() []ConnHook {
+	hooks := make([]ConnHook, 0, len(o.Hooks)+1)
+	if o.Capture != nil {
+		hooks = append(hooks, CaptureHook(o.Capture))
+	}
+	return append(hooks, o.Hooks...)
 }
 
 // NewProxier creates a new proxier instance
@@ -265,6 +305,12 @@ func (p *Proxier) reconcile(key string) error {
 		return nil
 	}
 
+	if p.opts.Direct {
+		// ClusterIPs don't change, and are routed to whichever endpoints
+		// the service has by the cluster
+		return nil
+	}
+
 	e, exists, err := p.endpointsInformer.GetStore().GetByKey(key)
 	if !exists || err != nil {
 		// no endpoints for service nothing we can do atm
@@ -301,9 +347,17 @@ func (p *Proxier) createPortforward(svc *corev1.Service, recreate string) {
 		return
 	}
 
-	ports := make([]string, len(svc.Spec.Ports))
+	ports := make([]ForwardedPort, len(svc.Spec.Ports))
 	for i, p := range resolvedPorts {
-		ports[i] = fmt.Sprintf("%d:%d", p.Port, p.TargetPort.IntValue())
+		ports[i] = ForwardedPort{
+			Name: p.Name,
+			// nolint: gosec // Why: ports are never negative
+			Port: uint(p.Port),
+			// nolint: gosec // Why: ports are never negative
+			TargetPort: uint(p.TargetPort.IntValue()),
+			// nolint: gosec // Why: ports are never negative
+			LocalPort: uint(p.Port),
+		}
 	}
 	req := CreatePortForwardRequest{
 		Service: info,
@@ -312,9 +366,15 @@ func (p *Proxier) createPortforward(svc *corev1.Service, recreate string) {
 			info.Name,
 			fmt.Sprintf("%s.%s", info.Name, info.Namespace),
 			fmt.Sprintf("%s.%s.svc", info.Name, info.Namespace),
-			fmt.Sprintf("%s.%s.svc.%s", info.Name, info.Namespace, p.opts.ClusterDomain),
+			p.serviceHostname(&info),
 		},
 	}
+	for _, ip := range svc.Spec.ClusterIPs {
+		// headless services have a ClusterIP of None
+		if addr, err := netip.ParseAddr(ip); err == nil {
+			req.ClusterIPs = append(req.ClusterIPs, addr)
+		}
+	}
 	// hack for basic support of stateful sets.
 	// grab the first endpoint to build the name. This sucks, but it's
 	// needed for Outreach's usecases. Please remove this.
@@ -350,6 +410,11 @@ func (p *Proxier) createPortforward(svc *corev1.Service, recreate string) {
 	}
 }
 
+// serviceHostname returns the fully qualified hostname of a service
+func (p *Proxier) serviceHostname(info *ServiceInfo) string {
+	return fmt.Sprintf("%s.%s.svc.%s", info.Name, info.Namespace, p.opts.ClusterDomain)
+}
+
 func (p *Proxier) List(ctx context.Context) ([]ServiceStatus, error) {
 	if p.worker == nil {
 		return nil, fmt.Errorf("proxier not running")
@@ -357,9 +422,14 @@ func (p *Proxier) List(ctx context.Context) ([]ServiceStatus, error) {
 
 	statuses := make([]ServiceStatus, 0)
 	for _, pf := range p.worker.portForwards {
-		ip := pf.IP.String()
-		if !pf.IP.IsValid() {
-			ip = ""
+		ip := ""
+		if pf.IP.IsValid() {
+			ip = pf.IP.String()
+		}
+
+		ipv6 := ""
+		if pf.IPv6.IsValid() {
+			ipv6 = pf.IPv6.String()
 		}
 
 		statuses = append(statuses, ServiceStatus{
@@ -368,6 +438,8 @@ func (p *Proxier) List(ctx context.Context) ([]ServiceStatus, error) {
 			Reason:      pf.StatusReason,
 			Statuses:    []PortForwardStatus{pf.Status},
 			IP:          ip,
+			IPv6:        ipv6,
+			Hostname:    p.serviceHostname(&pf.Service),
 			Ports:       pf.Ports,
 		})
 	}
@@ -0,0 +1,199 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the resolution of gateway hosts to pods.
+package proxier
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/netip"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// resolve returns the pod, and its port, that connections to host:port
+// are sent to. host is the address of a service or pod, or a hostname
+// like the ones served by the cluster's DNS:
+//
+//	<service>[.<namespace>[.svc[.<cluster domain>]]]
+//	<pod>.<service>.<namespace>[.svc[.<cluster domain>]]
+//
+// Like in the cluster, only ClusterIPs map the port to a target port,
+// pods of headless services are connected to on the port itself.
+func (g *Gateway) resolve(host string, port uint) (*ConnInfo, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.resolveAddr(addr.Unmap(), port)
+	}
+
+	name := strings.TrimSuffix(strings.ToLower(host), ".")
+	name = strings.TrimSuffix(name, "."+g.opts.ClusterDomain)
+	name = strings.TrimSuffix(name, ".svc")
+
+	parts := strings.Split(name, ".")
+	switch len(parts) {
+	case 1:
+		svc, err := g.serviceByName(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		return g.resolveService(svc, "", port)
+	case 2, 3:
+		svc, err := g.services.Services(parts[len(parts)-1]).Get(parts[len(parts)-2])
+		if kerrors.IsNotFound(err) {
+			return nil, errors.Wrap(errNotFound, host)
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to get service")
+		}
+
+		hostname := ""
+		if len(parts) == 3 {
+			hostname = parts[0]
+		}
+		return g.resolveService(svc, hostname, port)
+	}
+
+	return nil, errors.Wrap(errNotFound, host)
+}
+
+// serviceByName returns the service called name, which has to be unique
+// across namespaces
+func (g *Gateway) serviceByName(name string) (*corev1.Service, error) {
+	services, err := g.services.List(labels.Everything())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list services")
+	}
+
+	var found *corev1.Service
+	for _, svc := range services {
+		if svc.Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("service %s exists in multiple namespaces, use %s.<namespace>", name, name)
+		}
+		found = svc
+	}
+	if found == nil {
+		return nil, errors.Wrap(errNotFound, name)
+	}
+
+	return found, nil
+}
+
+// resolveAddr returns the pod for the ClusterIP of a service or the
+// address of a pod
+func (g *Gateway) resolveAddr(addr netip.Addr, port uint) (*ConnInfo, error) {
+	services, err := g.services.List(labels.Everything())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list services")
+	}
+	for _, svc := range services {
+		for _, ip := range svc.Spec.ClusterIPs {
+			if ip == addr.String() {
+				return g.resolveService(svc, "", port)
+			}
+		}
+	}
+
+	pods, err := g.pods.List(labels.Everything())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		for _, ip := range pod.Status.PodIPs {
+			if ip.IP == addr.String() {
+				return &ConnInfo{
+					Pod:  PodInfo{Name: pod.Name, Namespace: pod.Namespace},
+					Port: ForwardedPort{Port: port, TargetPort: port, LocalPort: port},
+				}, nil
+			}
+		}
+	}
+
+	return nil, errors.Wrap(errNotFound, addr.String())
+}
+
+// resolveService returns a random ready pod of svc, and the port on it
+// that port is sent to. If hostname is set, only the pod with that
+// hostname, or name, is used.
+func (g *Gateway) resolveService(svc *corev1.Service, hostname string, port uint) (*ConnInfo, error) {
+	info := &ConnInfo{
+		Service: ServiceInfo{Name: svc.Name, Namespace: svc.Namespace},
+		Port:    ForwardedPort{Port: port, TargetPort: port, LocalPort: port},
+	}
+
+	var servicePort *corev1.ServicePort
+	if hostname == "" && svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		for i := range svc.Spec.Ports {
+			p := &svc.Spec.Ports[i]
+			// nolint: gosec // Why: ports are never negative
+			if uint(p.Port) == port && (p.Protocol == "" || p.Protocol == corev1.ProtocolTCP) {
+				servicePort = p
+				break
+			}
+		}
+		if servicePort == nil {
+			return nil, errors.Wrapf(errNotFound, "service %s has no tcp port %d", info.Service.Key(), port)
+		}
+		info.Port.Name = servicePort.Name
+	}
+
+	endpoints, err := g.endpoints.Endpoints(svc.Namespace).Get(svc.Name)
+	if kerrors.IsNotFound(err) {
+		return nil, errors.Wrapf(errNoEndpoints, "service %s", info.Service.Key())
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get endpoints")
+	}
+
+	type candidate struct {
+		pod        string
+		targetPort uint
+	}
+	candidates := make([]candidate, 0)
+	for _, subset := range endpoints.Subsets {
+		targetPort := port
+		if servicePort != nil {
+			// endpoint ports have the name of their service port
+			targetPort = 0
+			for _, p := range subset.Ports {
+				if p.Name == servicePort.Name {
+					// nolint: gosec // Why: ports are never negative
+					targetPort = uint(p.Port)
+				}
+			}
+			if targetPort == 0 {
+				continue
+			}
+		}
+
+		for _, addr := range subset.Addresses {
+			if addr.TargetRef == nil || addr.TargetRef.Kind != PodKind {
+				continue
+			}
+			if hostname != "" && addr.Hostname != hostname && addr.TargetRef.Name != hostname {
+				continue
+			}
+			candidates = append(candidates, candidate{pod: addr.TargetRef.Name, targetPort: targetPort})
+		}
+	}
+
+	if len(candidates) == 0 {
+		if hostname != "" {
+			return nil, errors.Wrapf(errNotFound, "service %s has no pod %s", info.Service.Key(), hostname)
+		}
+		return nil, errors.Wrapf(errNoEndpoints, "service %s", info.Service.Key())
+	}
+
+	c := candidates[rand.IntN(len(candidates))] //nolint:gosec // Why: only used for load balancing
+	info.Pod = PodInfo{Name: c.pod, Namespace: svc.Namespace}
+	info.Port.TargetPort = c.targetPort
+	return info, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the SOCKS5 (RFC 1928) protocol spoken by the gateway.
+package proxier
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/netip"
+
+	"github.com/pkg/errors"
+)
+
+// socks5Version is the first byte of every SOCKS5 message
+const socks5Version = 0x05
+
+// SOCKS5 authentication methods, commands and address types
+const (
+	socksAuthNone         = 0x00
+	socksAuthNoAcceptable = 0xff
+
+	socksCommandConnect = 0x01
+
+	socksAddressIPv4   = 0x01
+	socksAddressDomain = 0x03
+	socksAddressIPv6   = 0x04
+)
+
+// SOCKS5 reply codes
+const (
+	socksSucceeded          = 0x00
+	socksGeneralFailure     = 0x01
+	socksNotAllowed         = 0x02
+	socksHostUnreachable    = 0x04
+	socksConnectionRefused  = 0x05
+	socksCommandUnsupported = 0x07
+	socksAddressUnsupported = 0x08
+)
+
+// readSOCKS5Request negotiates with a SOCKS5 client, which has to accept
+// connecting without authentication, and reads its CONNECT request.
+// Replies are written to w.
+func readSOCKS5Request(r *bufio.Reader, w io.Writer) (*proxyRequest, error) {
+	// version, number of methods, methods
+	var greeting [2]byte
+	if _, err := io.ReadFull(r, greeting[:]); err != nil {
+		return nil, errors.Wrap(err, "failed to read greeting")
+	}
+	methods := make([]byte, greeting[1])
+	if _, err := io.ReadFull(r, methods); err != nil {
+		return nil, errors.Wrap(err, "failed to read authentication methods")
+	}
+
+	if !bytes.Contains(methods, []byte{socksAuthNone}) {
+		w.Write([]byte{socks5Version, socksAuthNoAcceptable}) //nolint:errcheck // Why: the request failed anyways
+		return nil, fmt.Errorf("client requires authentication")
+	}
+	if _, err := w.Write([]byte{socks5Version, socksAuthNone}); err != nil {
+		return nil, err
+	}
+
+	// version, command, reserved, address type
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, errors.Wrap(err, "failed to read request")
+	}
+	if header[0] != socks5Version {
+		return nil, fmt.Errorf("unsupported socks version %d", header[0])
+	}
+	if header[1] != socksCommandConnect {
+		writeSOCKS5Reply(w, socksCommandUnsupported) //nolint:errcheck // Why: the request failed anyways
+		return nil, fmt.Errorf("unsupported socks command %d", header[1])
+	}
+
+	req := &proxyRequest{reply: func(err error) error {
+		return writeSOCKS5Reply(w, socksReplyCode(err))
+	}}
+	switch header[3] {
+	case socksAddressIPv4:
+		var addr [4]byte
+		if _, err := io.ReadFull(r, addr[:]); err != nil {
+			return nil, errors.Wrap(err, "failed to read address")
+		}
+		req.host = netip.AddrFrom4(addr).String()
+	case socksAddressIPv6:
+		var addr [16]byte
+		if _, err := io.ReadFull(r, addr[:]); err != nil {
+			return nil, errors.Wrap(err, "failed to read address")
+		}
+		req.host = netip.AddrFrom16(addr).String()
+	case socksAddressDomain:
+		length, err := r.ReadByte()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read address")
+		}
+		name := make([]byte, length)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return nil, errors.Wrap(err, "failed to read address")
+		}
+		req.host = string(name)
+	default:
+		writeSOCKS5Reply(w, socksAddressUnsupported) //nolint:errcheck // Why: the request failed anyways
+		return nil, fmt.Errorf("unsupported socks address type %d", header[3])
+	}
+
+	if err := binary.Read(r, binary.BigEndian, &req.port); err != nil {
+		return nil, errors.Wrap(err, "failed to read port")
+	}
+	return req, nil
+}
+
+// writeSOCKS5Reply writes a reply with code. The bound address isn't
+// meaningful for a port-forward, so it's always 0.0.0.0:0.
+func writeSOCKS5Reply(w io.Writer, code byte) error {
+	_, err := w.Write([]byte{socks5Version, code, 0x00, socksAddressIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+// socksReplyCode returns the reply code reporting err
+func socksReplyCode(err error) byte {
+	switch {
+	case err == nil:
+		return socksSucceeded
+	case errors.Is(err, errNotFound):
+		return socksHostUnreachable
+	case errors.Is(err, errNoEndpoints):
+		return socksConnectionRefused
+	case errors.Is(err, errRejected):
+		return socksNotAllowed
+	default:
+		return socksGeneralFailure
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the dialer that opens port-forward streams to pods.
+package proxier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/tools/portforward"
+)
+
+// errDeadlineUnsupported is returned when setting the deadline of a
+// port-forward stream
+var errDeadlineUnsupported = errors.New("port-forward streams don't support deadlines")
+
+// portForwardDialTimeout is how long a connection waits for the
+// port-forward connection to be created
+const portForwardDialTimeout = 30 * time.Second
+
+// podDialer opens a stream to a pod for every connection, over a single
+// port-forward connection. The port-forward connection is created when
+// the first stream is opened, and recreated if it's closed.
+type podDialer struct {
+	log    logrus.FieldLogger
+	dialer httpstream.Dialer
+
+	// onFailure is called, once, when the port-forward connection can't
+	// be created, e.g. because the pod is gone
+	onFailure func(err error)
+	failOnce  sync.Once
+
+	mu     sync.Mutex
+	conn   httpstream.Connection
+	closed bool
+
+	// dialing is the port-forward connection being created, which every
+	// stream opened in the meantime waits for, nil when there's none
+	dialing *dialAttempt
+
+	requestID atomic.Int64
+}
+
+// dialAttempt is the creation of a port-forward connection, conn and err
+// are set once done is closed
+type dialAttempt struct {
+	done chan struct{}
+	conn httpstream.Connection
+	err  error
+}
+
+// newPodDialer creates a podDialer that creates port-forward connections
+// through dialer
+func newPodDialer(log logrus.FieldLogger, dialer httpstream.Dialer, onFailure func(err error)) *podDialer {
+	return &podDialer{log: log, dialer: dialer, onFailure: onFailure}
+}
+
+// connection returns the port-forward connection, creating it if there
+// isn't one. It's created without holding the lock, so that other
+// streams and Close don't wait for it, and concurrent callers share it.
+func (d *podDialer) connection(ctx context.Context) (httpstream.Connection, error) {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("port-forward was closed")
+	}
+	if d.conn != nil {
+		conn := d.conn
+		d.mu.Unlock()
+		return conn, nil
+	}
+
+	a := d.dialing
+	if a == nil {
+		a = &dialAttempt{done: make(chan struct{})}
+		d.dialing = a
+		go d.dial(a)
+	}
+	d.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, portForwardDialTimeout)
+	defer cancel()
+
+	// the dial can't be canceled, it keeps going for the next stream
+	select {
+	case <-a.done:
+		return a.conn, a.err
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "failed to wait for port-forward")
+	}
+}
+
+// dial creates the port-forward connection of a
+func (d *podDialer) dial(a *dialAttempt) {
+	defer close(a.done)
+
+	conn, _, err := d.dialer.Dial(portforward.PortForwardProtocolV1Name)
+
+	d.mu.Lock()
+	d.dialing = nil
+	if err == nil && d.closed {
+		conn.Close()
+		err = fmt.Errorf("port-forward was closed")
+	} else if err == nil {
+		d.conn = conn
+	}
+	d.mu.Unlock()
+
+	if err != nil {
+		a.err = errors.Wrap(err, "failed to create port-forward")
+		d.failOnce.Do(func() { d.onFailure(a.err) })
+		return
+	}
+	a.conn = conn
+
+	// forget the connection once it's closed, so the next stream
+	// recreates it
+	go func() {
+		<-conn.CloseChan()
+		d.log.Debug("port-forward connection closed")
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.conn == conn {
+			d.conn = nil
+		}
+	}()
+}
+
+// Dial opens a stream to the target port of port. It implements dialFunc.
+func (d *podDialer) Dial(ctx context.Context, port *ForwardedPort) (net.Conn, error) {
+	conn, err := d.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	headers.Set(corev1.StreamType, corev1.StreamTypeError)
+	headers.Set(corev1.PortHeader, strconv.FormatUint(uint64(port.TargetPort), 10))
+	headers.Set(corev1.PortForwardRequestIDHeader, strconv.FormatInt(d.requestID.Add(1), 10))
+	errorStream, err := conn.CreateStream(headers)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "failed to create error stream for port %d", port.TargetPort)
+	}
+	// we're not writing to this stream
+	errorStream.Close()
+
+	s := &streamConn{conn: conn, errorStream: errorStream, errs: make(chan error, 1), port: port.TargetPort}
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			s.errs <- errors.Wrapf(err, "failed to read error stream for port %d", port.TargetPort)
+		case len(message) > 0:
+			s.errs <- fmt.Errorf("failed to forward port %d: %s", port.TargetPort, message)
+		}
+		close(s.errs)
+	}()
+
+	headers.Set(corev1.StreamType, corev1.StreamTypeData)
+	s.data, err = conn.CreateStream(headers)
+	if err != nil {
+		conn.RemoveStreams(errorStream)
+		conn.Close()
+		return nil, errors.Wrapf(err, "failed to create data stream for port %d", port.TargetPort)
+	}
+
+	return s, nil
+}
+
+// Close closes the port-forward connection and stops creating new ones,
+// one that's being created is closed once it is
+func (d *podDialer) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.closed = true
+	if d.conn != nil {
+		d.conn.Close()
+		d.conn = nil
+	}
+}
+
+// streamConn is a connection to a pod over a port-forward stream
+type streamConn struct {
+	conn        httpstream.Connection
+	data        httpstream.Stream
+	errorStream httpstream.Stream
+	port        uint
+
+	// errs receives the error reported by the kubelet, if there is one
+	errs chan error
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Read implements net.Conn
+func (s *streamConn) Read(b []byte) (int, error) {
+	return s.data.Read(b)
+}
+
+// Write implements net.Conn
+func (s *streamConn) Write(b []byte) (int, error) {
+	return s.data.Write(b)
+}
+
+// CloseWrite tells the pod nothing else will be sent
+func (s *streamConn) CloseWrite() error {
+	return s.data.Close()
+}
+
+// Close implements net.Conn, returning the error reported by the
+// kubelet. Since that usually means the port-forward is broken, e.g. the
+// container is gone, the port-forward connection is closed too.
+func (s *streamConn) Close() error {
+	s.closeOnce.Do(func() {
+		// reset the data stream before waiting for the error stream,
+		// unsent data would otherwise block it
+		s.data.Reset() //nolint:errcheck // Why: best effort
+		s.closeErr = <-s.errs
+		s.conn.RemoveStreams(s.errorStream, s.data)
+		if s.closeErr != nil {
+			s.conn.Close()
+		}
+	})
+	return s.closeErr
+}
+
+// LocalAddr implements net.Conn
+func (s *streamConn) LocalAddr() net.Addr {
+	return streamAddr("local")
+}
+
+// RemoteAddr implements net.Conn
+func (s *streamConn) RemoteAddr() net.Addr {
+	return streamAddr(strconv.FormatUint(uint64(s.port), 10))
+}
+
+// SetDeadline implements net.Conn
+func (s *streamConn) SetDeadline(time.Time) error {
+	return errDeadlineUnsupported
+}
+
+// SetReadDeadline implements net.Conn
+func (s *streamConn) SetReadDeadline(time.Time) error {
+	return errDeadlineUnsupported
+}
+
+// SetWriteDeadline implements net.Conn
+func (s *streamConn) SetWriteDeadline(time.Time) error {
+	return errDeadlineUnsupported
+}
+
+// streamAddr is the address of a side of a port-forward stream
+type streamAddr string
+
+// Network implements net.Addr
+func (streamAddr) Network() string {
+	return "portforward"
+}
+
+// String implements net.Addr
+func (a streamAddr) String() string {
+	return string(a)
+}
@@ -19,8 +19,6 @@ package proxier
 import (
 	"fmt"
 	"net/netip"
-
-	"k8s.io/client-go/tools/portforward"
 )
 
 const PodKind = "Pod"
@@ -49,6 +47,35 @@ func (s *ServiceInfo) Key() string {
 	return fmt.Sprintf("%s/%s", s.Namespace, s.Name)
 }
 
+// ForwardedPort is a port of a service that is exposed by a port-forward
+type ForwardedPort struct {
+	// Name is the name of the service port, if it has one
+	Name string
+
+	// Port is the port exposed by the service
+	Port uint
+
+	// TargetPort is the port on the pod that traffic is sent to
+	TargetPort uint
+
+	// LocalPort is the port listened on locally. This is generally
+	// the same as Port, but is remapped to another port if Port is
+	// already in use on the local machine.
+	LocalPort uint
+}
+
+// String returns the port in the local:remote format used by
+// port-forwards.
+func (p *ForwardedPort) String() string {
+	return fmt.Sprintf("%d:%d", p.LocalPort, p.TargetPort)
+}
+
+// IsRemapped returns true if this port is listened on locally on a
+// port other than the one the service exposes.
+func (p *ForwardedPort) IsRemapped() bool {
+	return p.LocalPort != p.Port
+}
+
 // CreatePortForwardRequest is a request to create port-forward
 type CreatePortForwardRequest struct {
 	// Service is the service this port-forward implements.
@@ -60,11 +87,16 @@ type CreatePortForwardRequest struct {
 	Hostnames []string
 
 	// Ports are the ports this port-forward exposes
-	Ports []string
+	Ports []ForwardedPort
 
 	// Endpoint is the specific pod to use for this service.
 	Endpoint *PodInfo
 
+	// ClusterIPs are the addresses of the service, these are used
+	// instead of a port-forward when routing directly, see
+	// ProxyOpts.Direct
+	ClusterIPs []netip.Addr
+
 	// Recreate specifies if this should be recreated if it already
 	// exists
 	Recreate       bool
@@ -96,14 +128,25 @@ type PortForwardConnection struct {
 	Status       PortForwardStatus
 	StatusReason string
 
-	// IP that this port-forward allocates
-	IP        netip.Addr
+	// IP is the IPv4 address that this port-forward allocates
+	IP netip.Addr
+
+	// IPv6 is the IPv6 address that this port-forward allocates, this
+	// is only set when an IPv6 cidr was provided.
+	IPv6 netip.Addr
+
 	Hostnames []string
 
-	// Ports is a local -> remote port list
-	Ports []string
+	// Ports are the ports this port-forward exposes. The local port of
+	// these may differ from the request if they were already in use.
+	Ports []ForwardedPort
+
+	fwd    *forwarder
+	dialer *podDialer
 
-	pf *portforward.PortForwarder
+	// direct is true if the service is reached through its ClusterIPs
+	// rather than a port-forward
+	direct bool
 }
 
 type PortForwardStatus string
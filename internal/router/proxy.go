@@ -0,0 +1,227 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the header based HTTP router.
+package router
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Rule matches requests based on a header, or a percentage of requests
+type Rule struct {
+	// Header is the name of the header to match
+	Header string
+
+	// Value is the value the header must have, if empty only the
+	// presence of the header is checked.
+	Value string
+
+	// Percent, if set instead of Header, matches that percentage of
+	// requests at random
+	Percent int
+}
+
+// PercentRule returns a rule matching percent of requests
+func PercentRule(percent int) (Rule, error) {
+	if percent < 1 || percent > 100 {
+		return Rule{}, fmt.Errorf("invalid percentage %d, expected 1-100", percent)
+	}
+	return Rule{Percent: percent}, nil
+}
+
+// ParseRule parses a rule in the format name=value, name, or N% for a
+// percentage of requests
+func ParseRule(s string) (Rule, error) {
+	if n, ok := strings.CutSuffix(s, "%"); ok && !strings.Contains(s, "=") {
+		percent, err := strconv.Atoi(n)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid percentage rule %q, expected N%%", s)
+		}
+		return PercentRule(percent)
+	}
+
+	name, value, _ := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Rule{}, fmt.Errorf("invalid header rule %q, expected name=value", s)
+	}
+
+	return Rule{Header: textproto.CanonicalMIMEHeaderKey(name), Value: value}, nil
+}
+
+// String returns the rule in the format accepted by ParseRule
+func (r Rule) String() string {
+	if r.Percent != 0 {
+		return strconv.Itoa(r.Percent) + "%"
+	}
+	if r.Value == "" {
+		return r.Header
+	}
+	return r.Header + "=" + r.Value
+}
+
+// Matches returns true if the request satisfies the rule
+func (r Rule) Matches(req *http.Request) bool {
+	if r.Percent != 0 {
+		return rand.IntN(100) < r.Percent //nolint:gosec // Why: only used to split traffic
+	}
+
+	values, ok := req.Header[textproto.CanonicalMIMEHeaderKey(r.Header)]
+	if !ok {
+		return false
+	}
+
+	if r.Value == "" {
+		return true
+	}
+
+	for _, v := range values {
+		if v == r.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// Router is a HTTP/1.1 and HTTP/2 (h2c), and thus gRPC, reverse proxy
+// that sends requests matching all of its rules to a local address and
+// everything else to an origin address. Combining a percentage rule with
+// header rules sends that percentage of the requests with the headers.
+type Router struct {
+	log   logrus.FieldLogger
+	rules []Rule
+
+	local  *httputil.ReverseProxy
+	origin *httputil.ReverseProxy
+}
+
+// New creates a new router. Requests that match every rule are sent to
+// localAddr, everything else is sent to originAddr.
+func New(log logrus.FieldLogger, rules []Rule, localAddr, originAddr string) *Router {
+	return &Router{
+		log:    log,
+		rules:  rules,
+		local:  newReverseProxy(log.WithField("backend", "local"), localAddr),
+		origin: newReverseProxy(log.WithField("backend", "origin"), originAddr),
+	}
+}
+
+// Matches returns true if the request should be sent to the local
+// address
+func (r *Router) Matches(req *http.Request) bool {
+	if len(r.rules) == 0 {
+		return false
+	}
+
+	for _, rule := range r.rules {
+		if !rule.Matches(req) {
+			return false
+		}
+	}
+	return true
+}
+
+// ServeHTTP implements http.Handler
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.Matches(req) {
+		r.log.WithField("path", req.URL.Path).Debug("routing request to local backend")
+		r.local.ServeHTTP(w, req)
+		return
+	}
+
+	r.origin.ServeHTTP(w, req)
+}
+
+// Serve serves the router on the provided listener until the context
+// is canceled.
+func (r *Router) Serve(ctx context.Context, l net.Listener) error {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	srv := &http.Server{
+		Handler:           r,
+		Protocols:         protocols,
+		ReadHeaderTimeout: 30 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx) //nolint:errcheck // Why: Best effort
+	}()
+
+	if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// newReverseProxy creates a reverse proxy to addr that speaks the same
+// HTTP version to the backend as the client spoke to us. This is
+// required for gRPC, which only works over HTTP/2.
+func newReverseProxy(log logrus.FieldLogger, addr string) *httputil.ReverseProxy {
+	h1 := &http.Transport{
+		Proxy:               nil,
+		DialContext:         (&net.Dialer{Timeout: 10 * time.Second}).DialContext,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	h2Protocols := new(http.Protocols)
+	h2Protocols.SetUnencryptedHTTP2(true)
+	h2 := &http.Transport{
+		Proxy:           nil,
+		DialContext:     (&net.Dialer{Timeout: 10 * time.Second}).DialContext,
+		Protocols:       h2Protocols,
+		IdleConnTimeout: 90 * time.Second,
+	}
+
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Scheme = "http"
+			pr.Out.URL.Host = addr
+			pr.SetXForwarded()
+
+			// keep the Host header the client sent, backends might rely on
+			// it for virtual hosting
+			pr.Out.Host = pr.In.Host
+		},
+		Transport: transportFunc(func(req *http.Request) (*http.Response, error) {
+			if req.ProtoMajor == 2 {
+				return h2.RoundTrip(req)
+			}
+			return h1.RoundTrip(req)
+		}),
+		// Flush immediately so that streaming responses, like server
+		// streaming gRPC calls, aren't buffered.
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			log.WithError(err).WithField("path", req.URL.Path).Warn("failed to proxy request")
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+}
+
+// transportFunc is a function that implements http.RoundTripper
+type transportFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f transportFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package router.
+package router
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+// newBackend creates a HTTP/1.1 and h2c server that responds with its
+// name and the protocol of the request
+func newBackend(t *testing.T, name string) *httptest.Server {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Backend")
+		io.WriteString(w, r.Proto) //nolint:errcheck // Why: test
+		w.Header().Set("X-Backend", name)
+	}))
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRouter(t *testing.T) {
+	local := newBackend(t, "local")
+	origin := newBackend(t, "origin")
+
+	rule, err := ParseRule("x-dev-user=alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := New(logrus.New(), []Rule{rule}, local.Listener.Addr().String(), origin.Listener.Addr().String())
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Serve(ctx, l) //nolint:errcheck // Why: test
+
+	h2c := new(http.Protocols)
+	h2c.SetUnencryptedHTTP2(true)
+	clients := map[string]*http.Client{
+		"HTTP/1.1": {Transport: &http.Transport{}},
+		"HTTP/2.0": {Transport: &http.Transport{Protocols: h2c}},
+	}
+
+	for proto, client := range clients {
+		for _, user := range []string{"alice", "bob", ""} {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+l.Addr().String(), http.NoBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user != "" {
+				req.Header.Set("X-Dev-User", user)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedBackend := "origin"
+			if user == "alice" {
+				expectedBackend = "local"
+			}
+
+			got := []string{strings.TrimSpace(string(body)), resp.Trailer.Get("X-Backend")}
+			if diff := cmp.Diff([]string{proto, expectedBackend}, got); diff != "" {
+				t.Errorf("%s request for user %q was routed incorrectly (-want +got):\n%s", proto, user, diff)
+			}
+		}
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	tests := map[string]Rule{
+		"x-dev-user=alice": {Header: "X-Dev-User", Value: "alice"},
+		"x-debug":          {Header: "X-Debug"},
+		"x-query=a=b":      {Header: "X-Query", Value: "a=b"},
+		"25%":              {Percent: 25},
+		"x-ratio=50%":      {Header: "X-Ratio", Value: "50%"},
+	}
+
+	for input, expected := range tests {
+		got, err := ParseRule(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(expected, got); diff != "" {
+			t.Errorf("ParseRule(%q) (-want +got):\n%s", input, diff)
+		}
+		if roundTrip, err := ParseRule(got.String()); err != nil || roundTrip != got {
+			t.Errorf("ParseRule(%q.String()) = %v, %v", input, roundTrip, err)
+		}
+	}
+
+	for _, input := range []string{"=alice", "0%", "101%", "half%"} {
+		if _, err := ParseRule(input); err == nil {
+			t.Errorf("expected an error for rule %q", input)
+		}
+	}
+}
+
+func TestRule_MatchesPercent(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+
+	matched := 0
+	rule := Rule{Percent: 25}
+	for range 10000 {
+		if rule.Matches(req) {
+			matched++
+		}
+	}
+	if matched < 2000 || matched > 3000 {
+		t.Errorf("25%% rule matched %d out of 10000 requests", matched)
+	}
+
+	if !(Rule{Percent: 100}).Matches(req) {
+		t.Error("100% rule didn't match")
+	}
+}
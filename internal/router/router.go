@@ -0,0 +1,8 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package router.
+
+// Package router implements the L7 proxy used by expose to route
+// requests carrying a header, or a percentage of requests, to a
+// developer's machine.
+package router
@@ -0,0 +1,93 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package server.
+package server
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/internal/capture"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Capture implements the Capture RPC for the localizer gRPC server.
+//
+// This RPC records the connections of a service's port-forward and expose
+// tunnels until the client disconnects or the size limit is reached.
+// Since the socket can be connected to by anyone, only the user running
+// localizer can capture.
+func (h *GRPCServiceHandler) Capture(req *api.CaptureRequest, res api.LocalizerService_CaptureServer) error {
+	if err := authorizePeer(res.Context()); err != nil {
+		return err
+	}
+	if req.Namespace == "" || req.Service == "" {
+		return fmt.Errorf("namespace and service are required")
+	}
+	if req.Port > math.MaxUint16 {
+		return fmt.Errorf("invalid port %d", req.Port)
+	}
+
+	// nolint: gosec // Why: both are bounds checked
+	c := h.capture.Start(getKey(req.Namespace, req.Service), uint16(req.Port), int64(min(req.MaxBytes, math.MaxInt64)))
+	defer h.capture.Stop(c)
+
+	h.log.WithField("service", c.Service).Info("started capture")
+	defer h.log.WithField("service", c.Service).Info("stopped capture")
+
+	for {
+		select {
+		case <-res.Context().Done():
+			return nil
+		case <-c.Done():
+			// send what was recorded before the capture stopped
+			for {
+				select {
+				case e := <-c.Events():
+					if err := res.Send(captureEventToAPI(c, &e)); err != nil {
+						return err
+					}
+				default:
+					return nil
+				}
+			}
+		case e := <-c.Events():
+			if err := res.Send(captureEventToAPI(c, &e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// captureEventToAPI converts an event recorded by c into its API
+// representation
+func captureEventToAPI(c *capture.Capture, e *capture.Event) *api.CaptureEvent {
+	resp := &api.CaptureEvent{
+		Time:   timestamppb.New(e.Time),
+		Conn:   e.Conn,
+		Client: e.Client,
+		Server: e.Server,
+		Data:   e.Data,
+		// nolint: gosec // Why: counts are never negative
+		Dropped: uint64(c.Dropped()),
+	}
+
+	switch e.Type {
+	case capture.EventOpen:
+		resp.Type = api.CaptureEventType_CAPTURE_EVENT_TYPE_OPEN
+	case capture.EventData:
+		resp.Type = api.CaptureEventType_CAPTURE_EVENT_TYPE_DATA
+	case capture.EventClose:
+		resp.Type = api.CaptureEventType_CAPTURE_EVENT_TYPE_CLOSE
+	}
+
+	switch e.Direction {
+	case capture.DirectionClient:
+		resp.Direction = api.CaptureDirection_CAPTURE_DIRECTION_CLIENT
+	case capture.DirectionServer:
+		resp.Direction = api.CaptureDirection_CAPTURE_DIRECTION_SERVER
+	}
+
+	return resp
+}
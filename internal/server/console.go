@@ -0,0 +1,114 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains a logger that streams to the CLI.
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/sirupsen/logrus"
+)
+
+// consoleHook sends log entries to a stream of ConsoleResponses, until it
+// is closed. This allows long running operations, such as an expose, to
+// keep logging to the daemon once the RPC that started them has finished.
+type consoleHook struct {
+	mu     sync.Mutex
+	send   func(*api.ConsoleResponse) error
+	closed bool
+
+	// gen is incremented every time the hook is attached to a stream
+	gen int
+}
+
+// Levels implements logrus.Hook
+func (h *consoleHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel, logrus.InfoLevel}
+}
+
+// Fire implements logrus.Hook
+func (h *consoleHook) Fire(e *logrus.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil
+	}
+
+	level := api.ConsoleLevel_CONSOLE_LEVEL_INFO
+	switch e.Level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		level = api.ConsoleLevel_CONSOLE_LEVEL_ERROR
+	case logrus.WarnLevel:
+		level = api.ConsoleLevel_CONSOLE_LEVEL_WARN
+	case logrus.InfoLevel, logrus.DebugLevel, logrus.TraceLevel:
+	}
+
+	msg := e.Message
+	if err, ok := e.Data[logrus.ErrorKey]; ok {
+		msg = fmt.Sprintf("%s: %v", msg, err)
+	}
+
+	// the client went away, keep logging to the daemon
+	if err := h.send(&api.ConsoleResponse{Level: level, Message: msg}); err != nil {
+		h.closed = true
+	}
+	return nil
+}
+
+// Close stops sending log entries to the stream
+func (h *consoleHook) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+}
+
+// Attach sends log entries to another stream, until the returned function
+// is called. This is used to report the progress of stopping an expose to
+// a different RPC than the one that started it.
+func (h *consoleHook) Attach(send func(*api.ConsoleResponse) error) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.gen++
+	h.send = send
+	h.closed = false
+
+	gen := h.gen
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		// the hook was attached to another stream in the meantime
+		if h.gen == gen {
+			h.closed = true
+		}
+	}
+}
+
+// newConsoleLogger returns a logger that logs to the same place as log,
+// and also sends every entry to a stream of ConsoleResponses until the
+// returned hook is closed.
+func newConsoleLogger(log logrus.FieldLogger, send func(*api.ConsoleResponse) error) (logrus.FieldLogger, *consoleHook) {
+	base := logrus.NewEntry(logrus.StandardLogger())
+	if e, ok := log.(*logrus.Entry); ok {
+		base = e
+	}
+
+	hook := &consoleHook{send: send}
+
+	l := logrus.New()
+	l.SetOutput(base.Logger.Out)
+	l.SetFormatter(base.Logger.Formatter)
+	l.SetLevel(base.Logger.GetLevel())
+	l.SetReportCaller(base.Logger.ReportCaller)
+	hooks := make(logrus.LevelHooks)
+	for level, hs := range base.Logger.Hooks {
+		hooks[level] = append([]logrus.Hook{}, hs...)
+	}
+	l.ReplaceHooks(hooks)
+	l.AddHook(hook)
+
+	return l.WithFields(base.Data), hook
+}
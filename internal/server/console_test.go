@@ -0,0 +1,59 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package server.
+package server
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestNewConsoleLogger(t *testing.T) {
+	var out bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&out)
+
+	var sent []*api.ConsoleResponse
+	log, console := newConsoleLogger(base.WithField("component", "test"), func(r *api.ConsoleResponse) error {
+		sent = append(sent, r)
+		return nil
+	})
+
+	log.Info("creating pod")
+	log.Debug("not sent")
+	log.WithError(errors.New("connection refused")).Warn("tunnel died")
+	console.Close()
+	log.Error("only logged to the daemon")
+
+	expected := []*api.ConsoleResponse{
+		{Level: api.ConsoleLevel_CONSOLE_LEVEL_INFO, Message: "creating pod"},
+		{Level: api.ConsoleLevel_CONSOLE_LEVEL_WARN, Message: "tunnel died: connection refused"},
+	}
+	if diff := cmp.Diff(expected, sent, protocmp.Transform()); diff != "" {
+		t.Errorf("sent mismatch (-want +got):\n%s", diff)
+	}
+
+	if !bytes.Contains(out.Bytes(), []byte("only logged to the daemon")) {
+		t.Errorf("expected entries to still be logged to the daemon, got %q", out.String())
+	}
+
+	var stopSent []*api.ConsoleResponse
+	detach := console.Attach(func(r *api.ConsoleResponse) error {
+		stopSent = append(stopSent, r)
+		return nil
+	})
+	log.Info("draining connections")
+	detach()
+	log.Info("not sent after detaching")
+
+	expected = []*api.ConsoleResponse{{Level: api.ConsoleLevel_CONSOLE_LEVEL_INFO, Message: "draining connections"}}
+	if diff := cmp.Diff(expected, stopSent, protocmp.Transform()); diff != "" {
+		t.Errorf("attached sent mismatch (-want +got):\n%s", diff)
+	}
+}
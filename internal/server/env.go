@@ -0,0 +1,153 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package server.
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/internal/envvars"
+	"github.com/getoutreach/localizer/internal/proxier"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Env implements the Env RPC for the localizer gRPC server.
+//
+// This RPC returns connection information, and Kubernetes style service
+// environment variables, for services that currently have a tunnel.
+// Ports that were remapped locally are reflected in both.
+func (h *GRPCServiceHandler) Env(ctx context.Context, req *api.EnvRequest) (*api.EnvResponse, error) {
+	if h.p == nil {
+		return nil, status.Error(codes.FailedPrecondition,
+			"services aren't tunneled in proxy mode, connect to them through the proxy instead")
+	}
+
+	statuses, err := h.p.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return envResponse(statuses, req.Services)
+}
+
+// envResponse creates the response of the Env RPC from the statuses of
+// the tunneled services, only including the requested services, or
+// namespaces, if any were requested.
+func envResponse(statuses []proxier.ServiceStatus, services []string) (*api.EnvResponse, error) {
+	// Ensure that the output is stable when two services in different
+	// namespaces have the same name.
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ServiceInfo.Key() < statuses[j].ServiceInfo.Key()
+	})
+
+	// Track which explicitly requested services we've found so that we
+	// can tell the user about ones that don't exist.
+	requested := make(map[string]bool)
+	namespaces := make(map[string]bool)
+	for _, s := range services {
+		if strings.Contains(s, "/") {
+			requested[s] = false
+		} else {
+			namespaces[s] = true
+		}
+	}
+
+	resp := &api.EnvResponse{
+		Variables: make(map[string]string),
+		Services:  make([]*api.EnvService, 0),
+	}
+
+	// variables only contain the name of the service, so services with
+	// the same name in different namespaces can't be told apart
+	owners := make(map[string]string)
+	for i := range statuses {
+		s := &statuses[i]
+
+		key := s.ServiceInfo.Key()
+		if len(services) != 0 {
+			if _, ok := requested[key]; !ok && !namespaces[s.ServiceInfo.Namespace] {
+				continue
+			}
+			requested[key] = true
+		}
+
+		es := envServiceFromStatus(s)
+		es.Variables = envvars.FromService(envvarsServiceFromStatus(s))
+		for k, v := range es.Variables {
+			if owner, ok := owners[k]; ok && owner != key {
+				return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf(
+					"services %s and %s both set %s, pass a single namespace, or service, to pick one", owner, key, k))
+			}
+			owners[k] = key
+			resp.Variables[k] = v
+		}
+		resp.Services = append(resp.Services, es)
+	}
+
+	missing := make([]string, 0)
+	for key, found := range requested {
+		if !found {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return nil, status.Error(codes.NotFound,
+			fmt.Sprintf("no tunnel exists for service(s): %s", strings.Join(missing, ", ")))
+	}
+
+	return resp, nil
+}
+
+// envServiceFromStatus converts a proxier service status into the
+// connection information returned by the Env RPC.
+func envServiceFromStatus(s *proxier.ServiceStatus) *api.EnvService {
+	ports := make([]*api.EnvPort, len(s.Ports))
+	for i, p := range s.Ports {
+		ports[i] = &api.EnvPort{
+			Name:     p.Name,
+			Protocol: "TCP",
+			// nolint: gosec // Why: ports always fit into a uint32
+			Port: uint32(p.Port),
+			// nolint: gosec // Why: ports always fit into a uint32
+			LocalPort: uint32(p.LocalPort),
+		}
+	}
+
+	return &api.EnvService{
+		Namespace: s.ServiceInfo.Namespace,
+		Name:      s.ServiceInfo.Name,
+		Hostname:  s.Hostname,
+		Ip:        s.IP,
+		Ipv6:      s.IPv6,
+		Ports:     ports,
+	}
+}
+
+// envvarsServiceFromStatus converts a proxier service status into the
+// service representation used to create environment variables.
+func envvarsServiceFromStatus(s *proxier.ServiceStatus) *envvars.Service {
+	host := s.IP
+	if host == "" {
+		host = s.IPv6
+	}
+
+	ports := make([]envvars.Port, len(s.Ports))
+	for i, p := range s.Ports {
+		ports[i] = envvars.Port{
+			Name:      p.Name,
+			Port:      p.Port,
+			LocalPort: p.LocalPort,
+		}
+	}
+
+	return &envvars.Service{
+		Name:  s.ServiceInfo.Name,
+		Host:  host,
+		Ports: ports,
+	}
+}
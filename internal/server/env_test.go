@@ -0,0 +1,95 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package server.
+package server
+
+import (
+	"testing"
+
+	"github.com/getoutreach/localizer/internal/proxier"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEnvResponse(t *testing.T) {
+	service := func(namespace, name, ip string) proxier.ServiceStatus {
+		return proxier.ServiceStatus{
+			ServiceInfo: proxier.ServiceInfo{Namespace: namespace, Name: name},
+			IP:          ip,
+			Hostname:    name + "." + namespace + ".svc.cluster.local",
+			Ports:       []proxier.ForwardedPort{{Port: 80, TargetPort: 8080, LocalPort: 80}},
+		}
+	}
+	statuses := []proxier.ServiceStatus{
+		service("default", "web", "127.0.0.2"),
+		service("default", "api", "127.0.0.3"),
+		service("staging", "api", "127.0.0.4"),
+	}
+
+	tests := []struct {
+		name     string
+		services []string
+
+		// want are the keys of the services returned
+		want     []string
+		wantHost map[string]string
+		wantCode codes.Code
+	}{
+		{
+			name:     "namespace",
+			services: []string{"default"},
+			want:     []string{"default/api", "default/web"},
+			wantHost: map[string]string{"API_SERVICE_HOST": "127.0.0.3", "WEB_SERVICE_HOST": "127.0.0.2"},
+		},
+		{
+			name:     "service",
+			services: []string{"staging/api"},
+			want:     []string{"staging/api"},
+			wantHost: map[string]string{"API_SERVICE_HOST": "127.0.0.4"},
+		},
+		{
+			name:     "service and namespace",
+			services: []string{"staging/api", "default"},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "everything",
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "missing service",
+			services: []string{"default/web", "default/db"},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := envResponse(statuses, tt.services)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("envResponse() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			got := make([]string, len(resp.Services))
+			for i, s := range resp.Services {
+				got[i] = s.Namespace + "/" + s.Name
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("envResponse() services mismatch (-want +got):\n%s", diff)
+			}
+
+			gotHost := make(map[string]string)
+			for k, v := range resp.Variables {
+				if k == "API_SERVICE_HOST" || k == "WEB_SERVICE_HOST" {
+					gotHost[k] = v
+				}
+			}
+			if diff := cmp.Diff(tt.wantHost, gotHost); diff != "" {
+				t.Errorf("envResponse() variables mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -19,12 +19,16 @@ package server
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/getoutreach/localizer/internal/capture"
 	"github.com/getoutreach/localizer/internal/expose"
 	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/getoutreach/localizer/internal/router"
+	"github.com/getoutreach/localizer/internal/ssh"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"k8s.io/client-go/kubernetes"
@@ -36,9 +40,18 @@ import (
 
 func mapPorts(portMap []string, log logrus.FieldLogger, servicePorts []kube.ResolvedServicePort) error {
 	for _, portOverride := range portMap {
+		// remote=target sends a remote port to any local address, e.g. a
+		// Unix socket or another host
+		if remote, target, ok := strings.Cut(portOverride, "="); ok {
+			if err := mapPortToTarget(remote, target, log, servicePorts); err != nil {
+				return errors.Wrapf(err, "failed to parse port map '%s'", portOverride)
+			}
+			continue
+		}
+
 		spl := strings.Split(portOverride, ":")
 		if len(spl) != 2 {
-			return fmt.Errorf("invalid port map '%s', expected 'local:remote'", portOverride)
+			return fmt.Errorf("invalid port map '%s', expected 'local:remote' or 'remote=target'", portOverride)
 		}
 
 		local, err := strconv.ParseUint(spl[0], 10, 0)
@@ -65,10 +78,51 @@ func mapPorts(portMap []string, log logrus.FieldLogger, servicePorts []kube.Reso
 	return nil
 }
 
+// mapPortToTarget sets the local address of the remote port to target
+func mapPortToTarget(remote, target string, log logrus.FieldLogger, servicePorts []kube.ResolvedServicePort) error {
+	rem, err := strconv.ParseUint(remote, 10, 16)
+	if err != nil {
+		return err
+	}
+
+	t, err := ssh.ParseTarget(target)
+	if err != nil {
+		return err
+	}
+
+	for i := range servicePorts {
+		// nolint: gosec // Why: ports are never negative
+		if uint(servicePorts[i].TargetPort.IntValue()) == uint(rem) {
+			log.Debugf("mapping remote port %d -> %s locally", rem, t)
+			servicePorts[i].MappedAddress = t.String()
+		}
+	}
+	return nil
+}
+
 type newExpose struct {
 	ports       []kube.ResolvedServicePort
 	namespace   string
 	serviceName string
+	opts        *expose.ExposeOpts
+
+	// console streams the progress of the expose to the CLI
+	console *consoleHook
+
+	// result receives the first error of the expose, or nil once it's
+	// forwarding traffic
+	result chan error
+}
+
+// runningExpose is an expose managed by the Exposer
+type runningExpose struct {
+	fwd     *expose.ServiceForward
+	console *consoleHook
+
+	// done is closed once the expose has stopped, err is the error it
+	// stopped with
+	done chan struct{}
+	err  error
 }
 
 type Exposer struct {
@@ -77,10 +131,20 @@ type Exposer struct {
 	e     *expose.Client
 	log   logrus.FieldLogger
 
-	// parentCtx shuts down all exposers when canceled
+	// podOptions are the default options for expose pods
+	podOptions *expose.PodOptions
+
+	// parentCtx stops accepting new exposes when canceled, running
+	// exposes are stopped by Shutdown
 	parentCtx context.Context
 
+	// exposeCtx is the context running exposes are derived from, it's
+	// canceled by Shutdown
+	exposeCtx context.Context
+	stopAll   context.CancelFunc
+
 	portForwards map[string]context.CancelFunc
+	exposes      map[string]*runningExpose
 	pfMutex      sync.Mutex
 
 	workerChan chan newExpose
@@ -88,18 +152,24 @@ type Exposer struct {
 }
 
 // NewExposer creates a service that can maintain multiple expose instances
-func NewExposer(parentCtx context.Context, k kubernetes.Interface, kconf *rest.Config, log logrus.FieldLogger) (*Exposer, error) {
+func NewExposer(parentCtx context.Context, k kubernetes.Interface, kconf *rest.Config, log logrus.FieldLogger,
+	podOptions *expose.PodOptions, registry *capture.Registry, transport kube.PortForwardTransport) (*Exposer, error) {
 	log = log.WithField("component", "exposer")
 
-	e := expose.NewExposer(k, kconf, log)
+	e := expose.NewExposer(k, kconf, log, registry, transport)
+	exposeCtx, stopAll := context.WithCancel(context.WithoutCancel(parentCtx))
 
 	exp := &Exposer{
 		e:            e,
 		k:            k,
 		kconf:        kconf,
 		log:          log,
+		podOptions:   podOptions,
 		parentCtx:    parentCtx,
+		exposeCtx:    exposeCtx,
+		stopAll:      stopAll,
 		portForwards: make(map[string]context.CancelFunc),
+		exposes:      make(map[string]*runningExpose),
 		workerChan:   make(chan newExpose),
 		doneChan:     make(chan struct{}),
 	}
@@ -117,42 +187,56 @@ func (e *Exposer) worker() {
 	// when this exits we're done
 	defer close(e.doneChan)
 
-	wg := sync.WaitGroup{}
 	for {
 		select {
 		case <-e.parentCtx.Done():
-			e.log.Info("waiting for exposes to finish")
-
-			// wait for the connections to close
-			wg.Wait()
-			e.log.Info("expose instances finished")
-
 			return
 		case expMsg := <-e.workerChan:
 			key := getKey(expMsg.namespace, expMsg.serviceName)
 
+			// only the first result is sent, which is all the caller waits for
+			sendResult := func(err error) {
+				select {
+				case expMsg.result <- err:
+				default:
+				}
+			}
+
 			if e.portForwards[key] != nil {
-				// this one is already allocated, skip it
+				sendResult(fmt.Errorf("service '%s' is already exposed", key))
 				continue
 			}
 
-			exp, err := e.e.Expose(e.parentCtx, expMsg.ports, expMsg.namespace, expMsg.serviceName)
+			exp, err := e.e.Expose(e.parentCtx, expMsg.ports, expMsg.namespace, expMsg.serviceName, expMsg.opts)
 			if err != nil {
-				// TODO(jaredallard): send this error back
 				e.log.WithError(err).Error("failed to create expose")
+				sendResult(err)
 				continue
 			}
 
-			workerCtx, cancel := context.WithCancel(e.parentCtx)
+			workerCtx, cancel := context.WithCancel(e.exposeCtx)
+			running := &runningExpose{fwd: exp, console: expMsg.console, done: make(chan struct{})}
 
 			// take lock so we can start the expose
 			e.pfMutex.Lock()
 
 			// spin up goroutine that'll terminate itself later
 			go func(ctx context.Context) {
+				go func() {
+					select {
+					case <-ctx.Done():
+					case <-exp.Ready():
+						sendResult(nil)
+					}
+				}()
+
 				err := exp.Start(ctx)
+				running.err = err
 				if err != nil {
 					e.log.WithError(err).Error("expose exited with an error")
+					sendResult(err)
+				} else {
+					sendResult(fmt.Errorf("expose of '%s' was stopped before it was ready", key))
 				}
 
 				// if we exited we need to signify that we're now not taken
@@ -160,75 +244,256 @@ func (e *Exposer) worker() {
 				defer e.pfMutex.Unlock()
 
 				e.portForwards[key] = nil
-
-				wg.Done()
+				delete(e.exposes, key)
+				close(running.done)
 			}(workerCtx)
 
-			wg.Add(1)
 			e.portForwards[key] = cancel
+			e.exposes[key] = running
 			e.pfMutex.Unlock()
 		}
 	}
 }
 
-func (e *Exposer) Close(namespace, serviceName string) error {
+// Stop stops an expose, waiting until it has drained and restored the
+// service. The progress is sent to send.
+func (e *Exposer) Stop(ctx context.Context, namespace, serviceName string, send func(*api.ConsoleResponse) error) error {
 	k := getKey(namespace, serviceName)
-	if e.portForwards[k] == nil {
+
+	e.pfMutex.Lock()
+	cancel := e.portForwards[k]
+	running := e.exposes[k]
+	e.pfMutex.Unlock()
+
+	if cancel == nil || running == nil {
 		return fmt.Errorf("service '%s' isn't exposed", k)
 	}
 
-	// canceling the context doesn't ensure it's 100% closed, we need to do that at somepoint
-	e.portForwards[k]()
+	if running.console != nil {
+		detach := running.console.Attach(send)
+		defer detach()
+	}
+	cancel()
 
-	return nil
+	select {
+	case <-running.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// List returns the status of every running expose, sorted by namespace
+// and service
+func (e *Exposer) List() []expose.Status {
+	e.pfMutex.Lock()
+	exposes := make([]*expose.ServiceForward, 0, len(e.exposes))
+	for _, running := range e.exposes {
+		exposes = append(exposes, running.fwd)
+	}
+	e.pfMutex.Unlock()
+
+	statuses := make([]expose.Status, len(exposes))
+	for i, exp := range exposes {
+		statuses[i] = exp.Status()
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Namespace != statuses[j].Namespace {
+			return statuses[i].Namespace < statuses[j].Namespace
+		}
+		return statuses[i].Service < statuses[j].Service
+	})
+	return statuses
 }
 
-// Wait waits for all exposes to be shut down
+// Wait waits for the Exposer to stop accepting new exposes, which happens
+// once its parent context is canceled
 func (e *Exposer) Wait() {
 	<-e.doneChan
+}
+
+// Shutdown stops every running expose, waiting for them to drain and
+// restore their services until ctx is done. Exposes that failed to
+// restore their service, or didn't finish in time, are reported in the
+// returned error. Their sessions are kept, so they're restored once the
+// sessions expire.
+func (e *Exposer) Shutdown(ctx context.Context) error {
+	e.pfMutex.Lock()
+	running := make(map[string]*runningExpose, len(e.exposes))
+	for key, r := range e.exposes {
+		running[key] = r
+	}
+	e.pfMutex.Unlock()
+
+	if len(running) != 0 {
+		e.log.Infof("stopping %d expose(s)", len(running))
+	}
+	e.stopAll()
+
+	var failed []string
+	for key, r := range running {
+		select {
+		case <-r.done:
+			if r.err != nil {
+				failed = append(failed, fmt.Sprintf("%s (%v)", key, r.err))
+			}
+		case <-ctx.Done():
+			failed = append(failed, fmt.Sprintf("%s (didn't stop in time)", key))
+		}
+	}
+
+	if len(failed) != 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("failed to restore exposed services, they'll be restored once their sessions expire: %s",
+			strings.Join(failed, ", "))
+	}
 
 	e.log.Info("exposes cleaned up")
+	return nil
 }
 
-func (e *Exposer) Start(ports []kube.ResolvedServicePort, namespace, serviceName string) error {
-	e.workerChan <- newExpose{
+// Start exposes a service, waiting until its tunnel is forwarding traffic
+// or it fails. If ctx is canceled first, the expose keeps going in the
+// background.
+func (e *Exposer) Start(ctx context.Context, ports []kube.ResolvedServicePort, namespace, serviceName string,
+	opts *expose.ExposeOpts, console *consoleHook) error {
+	result := make(chan error, 1)
+	select {
+	case e.workerChan <- newExpose{
 		ports:       ports,
 		namespace:   namespace,
 		serviceName: serviceName,
+		opts:        opts,
+		console:     console,
+		result:      result,
+	}:
+	case <-e.parentCtx.Done():
+		return fmt.Errorf("localizer is shutting down")
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 
-	// TODO(jaredallard): propregate error
-	return nil
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
+// StopExpose implements the StopExpose RPC for the localizer gRPC server.
+//
+// The progress of draining the expose and restoring the service is
+// streamed to the client, and the RPC returns once the expose has stopped.
 func (h *GRPCServiceHandler) StopExpose(req *api.StopExposeRequest, res api.LocalizerService_StopExposeServer) error {
-	return h.exp.Close(req.Namespace, req.Service)
+	key := getKey(req.Namespace, req.Service)
+	if err := h.exp.Stop(res.Context(), req.Namespace, req.Service, res.Send); err != nil {
+		return err
+	}
+
+	return res.Send(&api.ConsoleResponse{
+		Level:   api.ConsoleLevel_CONSOLE_LEVEL_INFO,
+		Message: fmt.Sprintf("stopped exposing service %s", key),
+	})
 }
 
+// ExposeService implements the ExposeService RPC for the localizer gRPC server.
+//
+// The progress of the expose is streamed to the client, and the RPC only
+// returns once the tunnel is forwarding traffic or the expose failed.
 func (h *GRPCServiceHandler) ExposeService(req *api.ExposeServiceRequest, res api.LocalizerService_ExposeServiceServer) error {
-	log := h.log
+	log, console := newConsoleLogger(h.log, res.Send)
+	defer console.Close()
 	ctx := h.ctx
 
-	// discover the service's ports
 	key := fmt.Sprintf("%s/%s", req.Namespace, req.Service)
-	s, err := h.k.CoreV1().Services(req.Namespace).Get(ctx, req.Service, metav1.GetOptions{})
+	servicePorts, err := h.exposeServicePorts(ctx, log, req)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get service '%s'", key)
+		return err
 	}
 
-	if len(s.Spec.Ports) == 0 {
-		return fmt.Errorf("service had no defined ports")
+	// handle mapped ports
+	if err := mapPorts(req.PortMap, log, servicePorts); err != nil {
+		return err
 	}
 
-	servicePorts, err := kube.ResolveServicePorts(log, s)
+	podOptions, err := podOptionsFromAPI(req.PodOptions)
 	if err != nil {
-		return errors.Wrap(err, "failed to resolve service ports")
+		return errors.Wrap(err, "invalid pod options")
 	}
 
-	// handle mapped ports
-	if err := mapPorts(req.PortMap, log, servicePorts); err != nil {
+	opts := &expose.ExposeOpts{PodOptions: h.exp.podOptions.Merge(podOptions), Force: req.Force, Log: log, Create: req.Create}
+	if req.DrainTimeout != nil {
+		opts.DrainTimeout = req.DrainTimeout.AsDuration()
+	}
+	switch req.Mode {
+	case api.ExposeMode_EXPOSE_MODE_UNSPECIFIED, api.ExposeMode_EXPOSE_MODE_REPLACE:
+		opts.Mode = expose.ModeReplace
+	case api.ExposeMode_EXPOSE_MODE_INTERCEPT:
+		opts.Mode = expose.ModeIntercept
+	case api.ExposeMode_EXPOSE_MODE_ROUTE:
+		opts.Mode = expose.ModeRoute
+		for _, h := range req.Headers {
+			rule, err := router.ParseRule(h)
+			if err != nil {
+				return err
+			}
+			opts.Rules = append(opts.Rules, rule)
+		}
+		if req.Percent != 0 {
+			rule, err := router.PercentRule(int(req.Percent))
+			if err != nil {
+				return err
+			}
+			opts.Rules = append(opts.Rules, rule)
+		}
+	default:
+		return fmt.Errorf("unknown expose mode %s", req.Mode)
+	}
+
+	if err := h.exp.Start(res.Context(), servicePorts, req.Namespace, req.Service, opts, console); err != nil {
 		return err
 	}
 
-	return h.exp.Start(servicePorts, req.Namespace, req.Service)
+	log.Infof("exposed service %s, stop it with 'localizer expose --stop %s'", key, key)
+	return nil
+}
+
+// exposeServicePorts returns the ports to expose for a request, either from
+// the existing service or, when creating it, from the requested ports
+func (h *GRPCServiceHandler) exposeServicePorts(ctx context.Context, log logrus.FieldLogger,
+	req *api.ExposeServiceRequest) ([]kube.ResolvedServicePort, error) {
+	key := fmt.Sprintf("%s/%s", req.Namespace, req.Service)
+	if req.Create {
+		if len(req.Ports) == 0 {
+			return nil, fmt.Errorf("at least one port is required to create a service")
+		}
+
+		ports := make([]uint16, len(req.Ports))
+		for i, port := range req.Ports {
+			if port == 0 || port > 65535 {
+				return nil, fmt.Errorf("invalid port %d", port)
+			}
+			ports[i] = uint16(port)
+		}
+		return expose.NewServicePorts(ports), nil
+	}
+
+	// discover the service's ports
+	log.Infof("looking up service %s", key)
+	s, err := h.k.CoreV1().Services(req.Namespace).Get(ctx, req.Service, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get service '%s'", key)
+	}
+
+	if len(s.Spec.Ports) == 0 {
+		return nil, fmt.Errorf("service had no defined ports")
+	}
+
+	servicePorts, err := kube.ResolveServicePorts(log, s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve service ports")
+	}
+	return servicePorts, nil
 }
@@ -20,7 +20,11 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/netip"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -34,6 +38,10 @@ import (
 	"github.com/getoutreach/localizer/pkg/localizer"
 )
 
+// shutdownTimeout bounds how long shutting down, and restoring every
+// exposed service, can take
+const shutdownTimeout = 5 * time.Minute
+
 type GRPCService struct {
 	lis net.Listener
 	srv *grpc.Server
@@ -44,11 +52,38 @@ type GRPCService struct {
 type RunOpts struct {
 	ClusterDomain string
 	IPCidr        string
+	IPv6Cidr      string
 	KubeContext   string
 
 	// SkipNamespaces is a list of namespaces to skip when forwarding
 	// services. Defaults to "kube-system" when passed to NewGRPCService.
 	SkipNamespaces []string
+
+	// ExposePodOptions are the default options for pods created by
+	// expose, individual expose requests can override them.
+	ExposePodOptions *api.ExposePodOptions
+
+	// PortForwardTransport is the protocol port-forwards are tunneled
+	// over, either websocket (default) or spdy
+	PortForwardTransport string
+
+	// VPN routes the cluster's cidrs, VPNCIDRs or the discovered ones,
+	// through an agent created in VPNNamespace instead of port-forwarding
+	// every service, see the vpn package
+	VPN          bool
+	VPNCIDRs     []string
+	VPNNamespace string
+	VPNImage     string
+
+	// ProxyAddress enables proxy mode, where services are reached through
+	// a SOCKS5 and HTTP CONNECT proxy listening on it instead of being
+	// port-forwarded. It doesn't require root, see proxier.Gateway.
+	ProxyAddress string
+
+	// ProxyAllowRemote allows ProxyAddress to be reachable from other
+	// machines, which can then connect to the cluster since the proxy
+	// has no authentication
+	ProxyAllowRemote bool
 }
 
 func NewGRPCService(opts *RunOpts) *GRPCService {
@@ -83,31 +118,90 @@ func (g *GRPCService) CleanupPreviousInstance(ctx context.Context, log logrus.Fi
 
 	log.Warn("failed to contact existing instance, cleaning up socket")
 
-	return errors.Wrap(os.Remove(localizer.Socket), "failed to cleanup socket from old localizer instance")
+	return errors.Wrap(os.Remove(localizer.SocketPath()), "failed to cleanup socket from old localizer instance")
+}
+
+// checkProxyAddress returns an error if address isn't a loopback address,
+// unless allowRemote is set
+func checkProxyAddress(address string, allowRemote bool) error {
+	if allowRemote {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid proxy address %q", address)
+	}
+	if strings.EqualFold(host, "localhost") {
+		return nil
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && addr.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("proxy address %q isn't a loopback address, the proxy has no authentication so anyone that can reach it "+
+		"can connect to the cluster, pass --proxy-allow-remote to listen on it anyways", address)
+}
+
+// serverSocketPath returns the socket to listen on, which is private to
+// the user when not running as root, see localizer.UserSocketPath
+func serverSocketPath() (string, error) {
+	if os.Geteuid() == 0 || os.Getenv(localizer.SocketEnvVar) != "" {
+		return localizer.SocketPath(), nil
+	}
+
+	socket, err := localizer.UserSocketPath()
+	if err != nil {
+		return "", err
+	}
+	return socket, errors.Wrap(os.MkdirAll(filepath.Dir(socket), 0o700), "failed to create socket directory")
 }
 
 // Run starts a grpc server with the internal server handler
 func (g *GRPCService) Run(ctx context.Context, log logrus.FieldLogger) error {
-	if _, err := os.Stat(localizer.Socket); err == nil {
+	if g.opts.ProxyAddress != "" {
+		if err := checkProxyAddress(g.opts.ProxyAddress, g.opts.ProxyAllowRemote); err != nil {
+			return err
+		}
+	}
+
+	socket, err := serverSocketPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(socket); err == nil {
 		// if we found an existing instance, attempt to cleanup after it
 		if err := g.CleanupPreviousInstance(ctx, log); err != nil {
 			return err
 		}
 	}
 
-	l, err := net.Listen("unix", localizer.Socket)
+	l, err := net.Listen("unix", socket)
 	if err != nil {
 		return errors.Wrap(err, "failed to listen on socket")
 	}
-	defer os.Remove(localizer.Socket)
+	defer os.Remove(socket)
 
-	err = os.Chmod(localizer.Socket, 0o777)
+	// only a server run as root is shared by every user
+	mode := os.FileMode(0o600)
+	if os.Geteuid() == 0 {
+		mode = 0o777
+	}
+	err = os.Chmod(socket, mode)
 	if err != nil {
 		return err
 	}
 
 	g.lis = l
 
+	var proxyListener net.Listener
+	if g.opts.ProxyAddress != "" {
+		proxyListener, err = net.Listen("tcp", g.opts.ProxyAddress)
+		if err != nil {
+			return errors.Wrap(err, "failed to listen on proxy address")
+		}
+		defer proxyListener.Close()
+	}
+
 	// Trigger the population of our informers
 	kevents.GlobalCache.Apps().V1().Deployments().Informer()
 	kevents.GlobalCache.Apps().V1().StatefulSets().Informer()
@@ -115,12 +209,17 @@ func (g *GRPCService) Run(ctx context.Context, log logrus.FieldLogger) error {
 	kevents.GlobalCache.Core().V1().Endpoints().Informer()
 	kevents.GlobalCache.Core().V1().Pods().Informer()
 
+	// the Kill RPC shuts down the same way as a signal does
+	ctx, shutdown := context.WithCancel(ctx)
+	defer shutdown()
+
 	h, err := NewServiceHandler(ctx, log, g.opts)
 	if err != nil {
 		return err
 	}
+	h.shutdown = shutdown
 
-	g.srv = grpc.NewServer()
+	g.srv = grpc.NewServer(grpc.Creds(newPeerCredentials()))
 	reflection.Register(g.srv)
 	api.RegisterLocalizerServiceServer(g.srv, h)
 
@@ -132,7 +231,7 @@ func (g *GRPCService) Run(ctx context.Context, log logrus.FieldLogger) error {
 	}()
 
 	// One day Serve() will accept a context?
-	log.Infof("starting GRPC server on unix://%s", localizer.Socket)
+	log.Infof("starting GRPC server on unix://%s", socket)
 	go func() {
 		err := g.srv.Serve(g.lis)
 		if err != nil {
@@ -150,11 +249,50 @@ func (g *GRPCService) Run(ctx context.Context, log logrus.FieldLogger) error {
 		log.WithError(err).Error("failed to start exposer")
 	}
 
-	if err := h.p.Start(ctx); err != nil {
-		log.WithError(err).Error("failed to start proxy informers")
+	var wg sync.WaitGroup
+	if h.vpn != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// services resolve to their ClusterIPs, so they're unreachable
+			// without the VPN
+			if err := h.vpn.Start(ctx); err != nil {
+				log.WithError(err).Error("failed to start vpn, shutting down")
+				shutdown()
+			}
+		}()
+	}
+
+	if h.gateway != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			log.Infof("starting proxy on %s", proxyListener.Addr())
+			if err := h.gateway.Serve(ctx, proxyListener); err != nil {
+				log.WithError(err).Error("proxy exited, shutting down")
+				shutdown()
+			}
+		}()
+	}
+
+	if h.p != nil {
+		if err := h.p.Start(ctx); err != nil {
+			log.WithError(err).Error("failed to start proxy informers")
+		}
 	}
 
 	h.exp.Wait()
+	wg.Wait()
+
+	// restore every exposed service before exiting
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := h.exp.Shutdown(shutdownCtx); err != nil {
+		log.WithError(err).Error("failed to shut down cleanly")
+		return err
+	}
 
 	return nil
 }
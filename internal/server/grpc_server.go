@@ -18,6 +18,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -26,8 +27,11 @@ import (
 
 	///StartBlock(imports)
 	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/internal/capture"
+	"github.com/getoutreach/localizer/internal/expose"
 	"github.com/getoutreach/localizer/internal/kube"
 	"github.com/getoutreach/localizer/internal/proxier"
+	"github.com/getoutreach/localizer/internal/vpn"
 	///EndBlock(imports)
 )
 
@@ -44,6 +48,19 @@ type GRPCServiceHandler struct {
 	ctx   context.Context
 	exp   *Exposer
 	p     *proxier.Proxier
+
+	// capture records the connections of port-forwards and exposes
+	capture *capture.Registry
+
+	// vpn routes the cluster to this machine, if enabled
+	vpn *vpn.VPN
+
+	// gateway proxies connections to the cluster in proxy mode, where p
+	// isn't created
+	gateway *proxier.Gateway
+
+	// shutdown gracefully shuts down the server
+	shutdown context.CancelFunc
 	///EndBlock(grpcConfig)
 }
 
@@ -60,29 +77,79 @@ func NewServiceHandler(ctx context.Context, log logrus.FieldLogger, opts *RunOpt
 		return nil, errors.Wrap(err, "failed to create kube client")
 	}
 
-	exp, err := NewExposer(ctx, k, kconf, log)
+	podOptions, err := podOptionsFromAPI(opts.ExposePodOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid expose pod options")
+	}
+
+	// captures are shared by port-forwards and exposes
+	captures := capture.NewRegistry()
+
+	transport, err := kube.ParsePortForwardTransport(opts.PortForwardTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := NewExposer(ctx, k, kconf, log, expose.DefaultPodOptions().Merge(podOptions), captures, transport)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to start expose container")
 	}
 
-	p, err := proxier.NewProxier(ctx, k, kconf, log, &proxier.ProxyOpts{
+	var v *vpn.VPN
+	if opts.VPN {
+		//nolint:govet // Why: We're OK shadowing err
+		cidrs, err := vpn.ParseCIDRs(opts.VPNCIDRs)
+		if err != nil {
+			return nil, err
+		}
+
+		v = vpn.New(k, kconf, log, &vpn.Options{
+			Namespace: opts.VPNNamespace,
+			Image:     opts.VPNImage,
+			CIDRs:     cidrs,
+			Transport: transport,
+		})
+	}
+
+	proxyOpts := &proxier.ProxyOpts{
 		ClusterDomain:  opts.ClusterDomain,
 		IPCidr:         opts.IPCidr,
+		IPv6Cidr:       opts.IPv6Cidr,
 		SkipNamespaces: opts.SkipNamespaces,
-	})
-	if err != nil {
-		return nil, errors.Wrap(err, "Failed to create proxier")
+		Capture:        captures,
+		Direct:         opts.VPN,
+
+		PortForwardTransport: transport,
+	}
+
+	// In proxy mode nothing is port-forwarded until it's connected to
+	// through the gateway
+	var p *proxier.Proxier
+	var gw *proxier.Gateway
+	if opts.ProxyAddress != "" {
+		if opts.VPN {
+			return nil, fmt.Errorf("the vpn can't be used in proxy mode")
+		}
+		gw = proxier.NewGateway(k, kconf, log, proxyOpts)
+	} else {
+		p, err = proxier.NewProxier(ctx, k, kconf, log, proxyOpts)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create proxier")
+		}
 	}
 	///EndBlock(grpcInit)
 
 	return &GRPCServiceHandler{
 		log: log,
 		///StartBlock(grpcConfigInit)
-		k:     k,
-		kconf: kconf,
-		ctx:   ctx,
-		exp:   exp,
-		p:     p,
+		k:       k,
+		kconf:   kconf,
+		ctx:     ctx,
+		exp:     exp,
+		p:       p,
+		capture: captures,
+		vpn:     v,
+		gateway: gw,
 		///EndBlock(grpcConfigInit)
 	}, nil
 }
@@ -0,0 +1,30 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package server.
+package server
+
+import "testing"
+
+func TestCheckProxyAddress(t *testing.T) {
+	tests := []struct {
+		address     string
+		allowRemote bool
+		wantErr     bool
+	}{
+		{address: "127.0.0.1:1080"},
+		{address: "[::1]:1080"},
+		{address: "localhost:1080"},
+		{address: ":1080", wantErr: true},
+		{address: "0.0.0.0:1080", wantErr: true},
+		{address: "192.168.1.5:1080", wantErr: true},
+		{address: "0.0.0.0:1080", allowRemote: true},
+		{address: "1080", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if err := checkProxyAddress(tt.address, tt.allowRemote); (err != nil) != tt.wantErr {
+				t.Errorf("checkProxyAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -15,11 +15,13 @@ import (
 
 // Kill implements the Kill RPC for the localizer gRPC server.
 //
-// This RPC just kills the current localizer process. Note that it actually waits until
-// after the RPC returns (responds) before killing the process because if it kills it
-// before it attempts to respond, the transport will have been closed already, resulting
-// in a perceived error. Because of this stipulation, this RPC is only BEST EFFORT.
-func (*GRPCServiceHandler) Kill(ctx context.Context, _ *api.Empty) (*api.Empty, error) {
+// This RPC gracefully shuts down the current localizer process, the same way a SIGTERM
+// does, restoring every exposed service. If that takes longer than shutdownTimeout the
+// process is killed. Note that it actually waits until after the RPC returns (responds)
+// before shutting down because if it does so before it attempts to respond, the transport
+// will have been closed already, resulting in a perceived error. Because of this
+// stipulation, this RPC is only BEST EFFORT.
+func (h *GRPCServiceHandler) Kill(ctx context.Context, _ *api.Empty) (*api.Empty, error) {
 	p, err := os.FindProcess(os.Getpid())
 	if err != nil {
 		return nil, errors.Wrap(err, "find localizer process")
@@ -29,8 +31,16 @@ func (*GRPCServiceHandler) Kill(ctx context.Context, _ *api.Empty) (*api.Empty,
 		// Give the RPC time to respond. It doesn't need much time, because it is using the local network.
 		time.Sleep(time.Second * 1)
 
-		_ = os.Remove(localizer.Socket) //nolint:errcheck // Why: We can't do anything about this error, it's best effort.
-		_ = process.Kill()              //nolint:errcheck // Why: We can't do anything about this error, it's best effort.
+		h.log.Info("shutting down, requested by Kill")
+		h.shutdown()
+
+		// The process exits once it has shut down, so if we're still here
+		// it got stuck.
+		time.Sleep(shutdownTimeout + 10*time.Second)
+		h.log.Error("failed to shut down in time, killing localizer")
+
+		_ = os.Remove(localizer.SocketPath()) //nolint:errcheck // Why: We can't do anything about this error, it's best effort.
+		_ = process.Kill()                    //nolint:errcheck // Why: We can't do anything about this error, it's best effort.
 	}(p)
 
 	return &api.Empty{}, nil
@@ -19,34 +19,34 @@ package server
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/internal/proxier"
 )
 
 func (h *GRPCServiceHandler) List(ctx context.Context, req *api.ListRequest) (*api.ListResponse, error) {
-	statuses, err := h.p.List(ctx)
-	if err != nil {
-		return nil, err
+	// in proxy mode services aren't tunneled, so there are none to list
+	statuses := make([]proxier.ServiceStatus, 0)
+	if h.p != nil {
+		var err error
+		statuses, err = h.p.List(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	services := make([]*api.ListService, len(statuses))
 	for i := range statuses {
 		s := &statuses[i]
 
+		// ports are shown as the local port and the service port, which
+		// only differ when the port was remapped locally
 		ports := make([]string, len(s.Ports))
 		for i, p := range s.Ports {
-			servicePorts := strings.Split(p, ":")
-			if len(servicePorts) != 2 {
-				continue
-			}
-
-			sourcePort := servicePorts[0]
-			destPort := servicePorts[1]
-			if sourcePort == destPort {
-				ports[i] = sourcePort + "/tcp"
+			if p.LocalPort == p.Port {
+				ports[i] = fmt.Sprintf("%d/tcp", p.LocalPort)
 			} else {
-				ports[i] = fmt.Sprintf("%s->%s/tcp", sourcePort, destPort)
+				ports[i] = fmt.Sprintf("%d->%d/tcp", p.LocalPort, p.Port)
 			}
 		}
 
@@ -57,9 +57,37 @@ func (h *GRPCServiceHandler) List(ctx context.Context, req *api.ListRequest) (*a
 			StatusReason: s.Reason,
 			Status:       string(s.Statuses[0]),
 			Ip:           s.IP,
+			Ipv6:         s.IPv6,
 			Ports:        ports,
 		}
 	}
 
-	return &api.ListResponse{Services: services}, nil
+	exposeStatuses := h.exp.List()
+	exposes := make([]*api.ListExpose, len(exposeStatuses))
+	for i := range exposeStatuses {
+		s := &exposeStatuses[i]
+
+		targets := make([]*api.ExposeTarget, len(s.Targets))
+		for i, t := range s.Targets {
+			targets[i] = &api.ExposeTarget{
+				RemotePort: uint32(t.RemotePort), //nolint:gosec // Why: ports fit in an uint32
+				Address:    t.Address,
+				Reachable:  t.Reachable,
+			}
+		}
+
+		exposes[i] = &api.ListExpose{
+			Namespace:  s.Namespace,
+			Name:       s.Service,
+			Mode:       exposeModeToAPI(s.Mode),
+			Objects:    s.Objects,
+			Pod:        s.Pod,
+			State:      string(s.State),
+			Reconnects: uint32(s.Reconnects), //nolint:gosec // Why: never negative
+			LastError:  s.LastError,
+			Targets:    targets,
+		}
+	}
+
+	return &api.ListResponse{Services: services, Exposes: exposes}, nil
 }
@@ -0,0 +1,84 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the checking of which user is calling an RPC.
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// peerCredentials are the transport credentials of the server's socket.
+// They don't secure the connection, but record the user that connected
+// to it, see authorizePeer.
+type peerCredentials struct {
+	credentials.TransportCredentials
+}
+
+// newPeerCredentials creates peerCredentials
+func newPeerCredentials() credentials.TransportCredentials {
+	return &peerCredentials{TransportCredentials: insecure.NewCredentials()}
+}
+
+// peerInfo is the credentials.AuthInfo of a connection, uid is only set
+// if the user could be determined
+type peerInfo struct {
+	credentials.CommonAuthInfo
+
+	uid *uint32
+}
+
+// AuthType implements credentials.AuthInfo
+func (peerInfo) AuthType() string {
+	return "peercred"
+}
+
+// ServerHandshake implements credentials.TransportCredentials
+func (c *peerCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	info := peerInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}
+	if unixConn, ok := conn.(*net.UnixConn); ok {
+		if uid, err := peerUID(unixConn); err == nil {
+			info.uid = &uid
+		}
+	}
+	return conn, info, nil
+}
+
+// Clone implements credentials.TransportCredentials
+func (c *peerCredentials) Clone() credentials.TransportCredentials {
+	return &peerCredentials{TransportCredentials: c.TransportCredentials.Clone()}
+}
+
+// authorizePeer returns an error unless the caller of an RPC is root,
+// the user the server runs as, or the user that started it through sudo.
+// It's used for RPCs that expose the data sent over tunnels, since the
+// socket can be connected to by every user.
+func authorizePeer(ctx context.Context) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.PermissionDenied, "unknown caller")
+	}
+	info, ok := p.AuthInfo.(peerInfo)
+	if !ok || info.uid == nil {
+		return status.Error(codes.PermissionDenied, "failed to determine the user of the caller")
+	}
+
+	uid := *info.uid
+	if uid == 0 || int(uid) == os.Getuid() {
+		return nil
+	}
+	if sudoUID, err := strconv.ParseUint(os.Getenv("SUDO_UID"), 10, 32); err == nil && uint32(sudoUID) == uid {
+		return nil
+	}
+
+	return status.Error(codes.PermissionDenied, fmt.Sprintf("user %d isn't allowed to call this, only the user running localizer is", uid))
+}
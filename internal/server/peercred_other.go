@@ -0,0 +1,19 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the peerUID stub for unsupported platforms.
+
+//go:build !linux && !darwin
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// peerUID returns an error, peer credentials aren't supported on this
+// platform
+func peerUID(_ *net.UnixConn) (uint32, error) {
+	return 0, fmt.Errorf("peer credentials are not supported on %s", runtime.GOOS)
+}
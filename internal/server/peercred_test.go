@@ -0,0 +1,101 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package server.
+
+//go:build linux || darwin
+
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getoutreach/localizer/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestPeerCredentials(t *testing.T) {
+	l, err := net.Listen("unix", filepath.Join(t.TempDir(), "test.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	client, err := net.Dial("unix", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, info, err := newPeerCredentials().ServerHandshake(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid := info.(peerInfo).uid; uid == nil || int(*uid) != os.Getuid() {
+		t.Fatalf("ServerHandshake() uid = %v, expected %d", uid, os.Getuid())
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: info})
+	if err := authorizePeer(ctx); err != nil {
+		t.Errorf("authorizePeer() denied the user running the server: %v", err)
+	}
+
+	other := uint32(os.Getuid() + 4242)
+	t.Setenv("SUDO_UID", "")
+	ctx = peer.NewContext(context.Background(), &peer.Peer{AuthInfo: peerInfo{uid: &other}})
+	if err := authorizePeer(ctx); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("authorizePeer() = %v, expected another user to be denied", err)
+	}
+	ctx = peer.NewContext(context.Background(), &peer.Peer{AuthInfo: peerInfo{}})
+	if err := authorizePeer(ctx); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("authorizePeer() = %v, expected an unknown user to be denied", err)
+	}
+}
+
+// captureServer is a api.LocalizerService_CaptureServer that's only
+// used for its context
+type captureServer struct {
+	api.LocalizerService_CaptureServer
+
+	ctx context.Context
+}
+
+// Context implements api.LocalizerService_CaptureServer
+func (s *captureServer) Context() context.Context {
+	return s.ctx
+}
+
+func TestAuthorizedRPCs(t *testing.T) {
+	t.Setenv("SUDO_UID", "")
+	other := uint32(os.Getuid() + 4242)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: peerInfo{uid: &other}})
+
+	h := &GRPCServiceHandler{}
+	rpcs := map[string]func() error{
+		"Capture": func() error {
+			return h.Capture(&api.CaptureRequest{Namespace: "default", Service: "app"}, &captureServer{ctx: ctx})
+		},
+		"WorkloadConfig": func() error {
+			_, err := h.WorkloadConfig(ctx, &api.WorkloadConfigRequest{Namespace: "default", Service: "app"})
+			return err
+		},
+	}
+	for name, rpc := range rpcs {
+		t.Run(name, func(t *testing.T) {
+			if err := rpc(); status.Code(err) != codes.PermissionDenied {
+				t.Errorf("%s() = %v, expected another user to be denied", name, err)
+			}
+		})
+	}
+}
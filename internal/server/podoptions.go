@@ -0,0 +1,79 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package server.
+package server
+
+import (
+	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/internal/expose"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// podOptionsFromAPI converts the API representation of expose pod
+// options, returning nil if opts is nil.
+func podOptionsFromAPI(opts *api.ExposePodOptions) (*expose.PodOptions, error) {
+	if opts == nil {
+		return nil, nil
+	}
+
+	var tunnelImage expose.TunnelImage
+	if opts.TunnelImage != "" {
+		var err error
+		tunnelImage, err = expose.ParseTunnelImage(opts.TunnelImage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	requests, err := resourceListFromAPI(opts.Requests)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid requests")
+	}
+
+	limits, err := resourceListFromAPI(opts.Limits)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid limits")
+	}
+
+	tolerations := make([]corev1.Toleration, len(opts.Tolerations))
+	for i, t := range opts.Tolerations {
+		tolerations[i] = corev1.Toleration{
+			Key:      t.Key,
+			Operator: corev1.TolerationOperator(t.Operator),
+			Value:    t.Value,
+			Effect:   corev1.TaintEffect(t.Effect),
+		}
+	}
+
+	return &expose.PodOptions{
+		TunnelImage:        tunnelImage,
+		Image:              opts.Image,
+		AgentImage:         opts.AgentImage,
+		ImagePullSecrets:   opts.ImagePullSecrets,
+		Resources:          corev1.ResourceRequirements{Requests: requests, Limits: limits},
+		NodeSelector:       opts.NodeSelector,
+		Tolerations:        tolerations,
+		ServiceAccountName: opts.ServiceAccount,
+		Labels:             opts.Labels,
+		Annotations:        opts.Annotations,
+	}, nil
+}
+
+// resourceListFromAPI parses a map of resource names to quantities
+func resourceListFromAPI(m map[string]string) (corev1.ResourceList, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	list := make(corev1.ResourceList, len(m))
+	for name, value := range m {
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse quantity %q of %s", value, name)
+		}
+		list[corev1.ResourceName(name)] = q
+	}
+	return list, nil
+}
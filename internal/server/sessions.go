@@ -0,0 +1,56 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package server.
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/internal/expose"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListExposeSessions implements the ListExposeSessions RPC for the localizer gRPC server.
+//
+// This RPC returns the expose sessions of every developer in the cluster,
+// not just the ones created by this daemon.
+func (h *GRPCServiceHandler) ListExposeSessions(ctx context.Context, _ *api.Empty) (*api.ListExposeSessionsResponse, error) {
+	sessions, err := h.exp.e.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resp := &api.ListExposeSessionsResponse{Sessions: make([]*api.ExposeSession, len(sessions))}
+	for i := range sessions {
+		s := &sessions[i]
+		resp.Sessions[i] = &api.ExposeSession{
+			Namespace: s.Namespace,
+			Service:   s.Service,
+			Owner:     s.Owner,
+			Mode:      exposeModeToAPI(s.Mode),
+			Ports:     s.Ports,
+			Objects:   s.ObjectKeys(),
+			CreatedAt: timestamppb.New(s.CreatedAt),
+			ExpiresAt: timestamppb.New(s.ExpiresAt),
+			Expired:   s.Expired(now),
+		}
+	}
+
+	return resp, nil
+}
+
+// exposeModeToAPI converts an expose mode into its API representation
+func exposeModeToAPI(m expose.Mode) api.ExposeMode {
+	switch m {
+	case expose.ModeReplace:
+		return api.ExposeMode_EXPOSE_MODE_REPLACE
+	case expose.ModeIntercept:
+		return api.ExposeMode_EXPOSE_MODE_INTERCEPT
+	case expose.ModeRoute:
+		return api.ExposeMode_EXPOSE_MODE_ROUTE
+	}
+	return api.ExposeMode_EXPOSE_MODE_UNSPECIFIED
+}
@@ -9,8 +9,13 @@ import (
 	"github.com/getoutreach/localizer/api"
 )
 
-// Stable implements the Stable RPC for the localizer gRPC server.
+// Stable implements the Stable RPC for the localizer gRPC server. In
+// proxy mode there's nothing to wait for, so it's always stable.
 func (g *GRPCServiceHandler) Stable(ctx context.Context, _ *api.Empty) (*api.StableResponse, error) {
+	if g.p == nil {
+		return &api.StableResponse{Stable: true}, nil
+	}
+
 	return &api.StableResponse{
 		Stable: g.p.IsStable(),
 	}, nil
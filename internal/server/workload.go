@@ -0,0 +1,86 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package server.
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/getoutreach/localizer/api"
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadConfig implements the WorkloadConfig RPC for the localizer gRPC server.
+//
+// This RPC finds the workload powering a service, using the same logic
+// expose does, and returns the configuration of one of its containers.
+// Since that includes the contents of Secrets, only the user running
+// localizer can call it.
+func (h *GRPCServiceHandler) WorkloadConfig(ctx context.Context,
+	req *api.WorkloadConfigRequest) (*api.WorkloadConfigResponse, error) {
+	if err := authorizePeer(ctx); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s/%s", req.Namespace, req.Service)
+	s, err := h.k.CoreV1().Services(req.Namespace).Get(ctx, req.Service, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get service '%s'", key)
+	}
+
+	owners, err := h.exp.e.ServiceOwners(ctx, s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find workloads for service")
+	}
+	if len(owners) == 0 {
+		return nil, fmt.Errorf("failed to find any workloads for service '%s'", key)
+	}
+	owner := &owners[0]
+
+	spec, err := owner.PodSpec()
+	if err != nil {
+		return nil, err
+	}
+
+	container, err := kube.FindContainer(spec, req.Container)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := kube.ResolveContainerEnv(ctx, h.log, h.k, req.Namespace, container)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve container environment")
+	}
+
+	resp := &api.WorkloadConfigResponse{
+		Controller: fmt.Sprintf("%s/%s/%s", strings.ToLower(owner.Kind), owner.Namespace, owner.Name),
+		Container:  container.Name,
+		Env:        env,
+	}
+
+	if !req.IncludeFiles {
+		return resp, nil
+	}
+
+	files, err := kube.ResolveContainerFiles(ctx, h.log, h.k, req.Namespace, spec, container)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve container files")
+	}
+
+	resp.Files = make([]*api.WorkloadFile, len(files))
+	for i, f := range files {
+		resp.Files[i] = &api.WorkloadFile{
+			Path: f.Path,
+			Data: f.Data,
+			// nolint: gosec // Why: file modes are never negative
+			Mode:   uint32(f.Mode),
+			Secret: f.Secret,
+		}
+	}
+
+	return resp, nil
+}
@@ -21,13 +21,12 @@ import (
 	"fmt"
 	"io"
 	"net"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"strconv"
-
 	"github.com/function61/gokit/io/bidipipe"
+	"github.com/getoutreach/localizer/internal/capture"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
@@ -44,51 +43,38 @@ type Client struct {
 	port int
 
 	// ports is the ports this client currently hosts
-	// with the format being remotePort localPort
-	ports map[uint]uint
-}
+	// with the format being remotePort target
+	ports map[uint16]Target
 
-// NewReverseTunnelClient creates a new ssh powered reverse
-// tunnel client
-func NewReverseTunnelClient(l logrus.FieldLogger, host string, port int, ports []string) *Client {
-	portMap := make(map[uint]uint)
-	for _, portStr := range ports {
-		ports := strings.Split(portStr, ":")
-		if len(ports) == 0 {
-			return nil
-		}
+	// creds are used to authenticate with, and verify, the remote
+	// SSH server
+	creds *Credentials
 
-		localPort := 0
-		remotePort := 0
-		if len(ports) == 1 {
-			portInt, err := strconv.Atoi(ports[0])
-			if err != nil {
-				panic(err)
-			}
+	// ready is closed once the remote is listening on every port
+	ready chan struct{}
 
-			localPort = portInt
-			remotePort = portInt
-		}
-
-		if len(ports) == 2 {
-			lport, err := strconv.Atoi(ports[0])
-			if err != nil {
-				panic(err)
-			}
+	// active is the number of connections currently being tunneled
+	active atomic.Int64
 
-			rport, err := strconv.Atoi(ports[1])
-			if err != nil {
-				panic(err)
-			}
+	// capture records tunneled connections
+	capture *capture.Registry
+}
 
-			localPort = lport
-			remotePort = rport
+// NewReverseTunnelClient creates a new ssh powered reverse
+// tunnel client. Ports are in the format accepted by ParsePortMap,
+// connections are recorded to registry if it's not nil.
+func NewReverseTunnelClient(l logrus.FieldLogger, host string, port int, ports []string, creds *Credentials,
+	registry *capture.Registry) (*Client, error) {
+	portMap := make(map[uint16]Target, len(ports))
+	for _, portStr := range ports {
+		remotePort, target, err := ParsePortMap(portStr)
+		if err != nil {
+			return nil, err
 		}
-
-		// nolint: gosec // Why: port numbers are never negative.
-		portMap[uint(remotePort)] = uint(localPort)
+		portMap[remotePort] = target
 	}
-	return &Client{l, host, port, portMap}
+	return &Client{log: l, host: host, port: port, ports: portMap, creds: creds,
+		ready: make(chan struct{}), capture: registry}, nil
 }
 
 // Start starts the ssh tunnel. This blocks until
@@ -108,17 +94,9 @@ func (c *Client) Start(ctx context.Context, serviceKey string) error { //nolint:
 	}
 	defer conn.Close()
 
-	sconn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
-		User: "outreach",
-		Auth: []ssh.AuthMethod{
-			// TODO(jaredallard): consider randomizing this?
-			ssh.Password("supersecretpassword"),
-		},
-		//nolint:gosec // Why: We're not really caring about what we connect to here.
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	})
+	sconn, chans, reqs, err := ssh.NewClientConn(conn, addr, c.creds.ClientConfig())
 	if err != nil {
-		return err
+		return errors.Wrap(err, "failed to authenticate with remote ssh server")
 	}
 
 	sshClient := ssh.NewClient(sconn, chans, reqs)
@@ -148,21 +126,20 @@ func (c *Client) Start(ctx context.Context, serviceKey string) error { //nolint:
 	}()
 
 	wg := sync.WaitGroup{}
-	for remotePort, localPort := range c.ports {
+	for remotePort, target := range c.ports {
 		// reverse listen on remote server port
 		remoteAddr := fmt.Sprintf("0.0.0.0:%d", remotePort)
-		localAddr := fmt.Sprintf("127.0.0.1:%d", localPort)
 		listener, err := sshClient.Listen("tcp", remoteAddr)
 		if err != nil {
 			return errors.Wrapf(err, "failed to request remote to listen on %s", remoteAddr)
 		}
 
 		wg.Add(1)
-		go func(remotePort uint) {
+		go func(remotePort uint16) {
 			defer listener.Close()
 			defer wg.Done()
 
-			c.log.Infof("created tunnel from remote %s:%d to %s", serviceKey, remotePort, localAddr)
+			c.log.Infof("created tunnel from remote %s:%d to %s", serviceKey, remotePort, target)
 
 			// handle incoming connections on reverse forwarded tunnel
 			for {
@@ -181,24 +158,41 @@ func (c *Client) Start(ctx context.Context, serviceKey string) error { //nolint:
 
 				// handle the connection in another goroutine, so we can support multiple concurrent
 				// connections on the same port
-				go c.handleReverseForwardConn(client, localAddr)
+				go c.handleReverseForwardConn(ctx, serviceKey, remotePort, client, target)
 			}
 		}(remotePort)
 	}
 
+	close(c.ready)
 	wg.Wait()
 
 	return nil
 }
 
-func (c *Client) handleReverseForwardConn(client net.Conn, localAddr string) {
+// Ready returns a channel that's closed once the remote is listening on
+// every port, i.e. once traffic is being tunneled
+func (c *Client) Ready() <-chan struct{} {
+	return c.ready
+}
+
+// Active returns the number of connections currently being tunneled
+func (c *Client) Active() int {
+	return int(c.active.Load())
+}
+
+func (c *Client) handleReverseForwardConn(ctx context.Context, serviceKey string, remotePort uint16,
+	client net.Conn, target Target) {
 	defer client.Close()
 
-	remote, err := net.Dial("tcp", localAddr)
+	c.active.Add(1)
+	defer c.active.Add(-1)
+
+	remote, err := target.Dial(ctx)
 	if err != nil {
-		c.log.WithError(err).Errorf("failed to dial local service (is anything running on your machine at %q?)", localAddr)
+		c.log.WithError(err).Errorf("failed to dial local service (is anything listening at %q?)", target)
 		return
 	}
+	client = c.capture.Tap(serviceKey, remotePort, client, client.RemoteAddr().String(), target.Address)
 
 	// pipe data in both directions:
 	// - client => remote
@@ -0,0 +1,105 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the per-session credentials used by the reverse tunnel.
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// Username is the user the reverse tunnel client authenticates as
+const Username = "outreach"
+
+// Credentials are the keys used to authenticate both sides of a reverse
+// tunnel. They're generated for every expose session so that nothing
+// else in the cluster is able to connect to, or impersonate, the server.
+type Credentials struct {
+	client ssh.Signer
+	host   ssh.Signer
+
+	clientKey ed25519.PrivateKey
+	hostKey   ed25519.PrivateKey
+}
+
+// GenerateCredentials generates a new ed25519 client and host key
+func GenerateCredentials() (*Credentials, error) {
+	_, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate client key")
+	}
+	client, err := ssh.NewSignerFromKey(clientKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client signer")
+	}
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate host key")
+	}
+	host, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create host signer")
+	}
+
+	return &Credentials{client: client, host: host, clientKey: clientKey, hostKey: hostKey}, nil
+}
+
+// ClientKey returns the private key of the client
+func (c *Credentials) ClientKey() ed25519.PrivateKey {
+	return c.clientKey
+}
+
+// HostKey returns the private key of the host
+func (c *Credentials) HostKey() ed25519.PrivateKey {
+	return c.hostKey
+}
+
+// AuthorizedKey returns the public key of the client in the
+// authorized_keys format
+func (c *Credentials) AuthorizedKey() string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(c.client.PublicKey())))
+}
+
+// HostPublicKey returns the public key of the host in the
+// authorized_keys format, as used by ssh_host_ed25519_key.pub
+func (c *Credentials) HostPublicKey() string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(c.host.PublicKey())))
+}
+
+// HostPrivateKey returns the private key of the host in the OpenSSH
+// format, as used by ssh_host_ed25519_key
+func (c *Credentials) HostPrivateKey() ([]byte, error) {
+	block, err := ssh.MarshalPrivateKey(c.hostKey, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal host key")
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// HostSigner returns the signer for the host key, used by servers
+func (c *Credentials) HostSigner() ssh.Signer {
+	return c.host
+}
+
+// ClientConfig returns the configuration for a client that
+// authenticates with the client key and only accepts the host key
+func (c *Credentials) ClientConfig() *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User:              Username,
+		Auth:              []ssh.AuthMethod{ssh.PublicKeys(c.client)},
+		HostKeyCallback:   ssh.FixedHostKey(c.host.PublicKey()),
+		HostKeyAlgorithms: []string{ssh.KeyAlgoED25519},
+	}
+}
+
+// IsAuthorized returns true if the provided key is the client key, used
+// by servers
+func (c *Credentials) IsAuthorized(key ssh.PublicKey) bool {
+	return string(key.Marshal()) == string(c.client.PublicKey().Marshal())
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package ssh.
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// handshake runs a SSH handshake between a server using server's
+// credentials and a client using client's
+func handshake(t *testing.T, server, client *Credentials) error {
+	serverConf := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if server.IsAuthorized(key) {
+				return &ssh.Permissions{}, nil
+			}
+			return nil, fmt.Errorf("unauthorized key")
+		},
+	}
+	serverConf.AddHostKey(server.HostSigner())
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		serverConn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer serverConn.Close()
+
+		//nolint:errcheck // Why: the client reports the error
+		ssh.NewServerConn(serverConn, serverConf)
+	}()
+
+	clientConn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientConn.Close()
+
+	conn, _, _, err := ssh.NewClientConn(clientConn, l.Addr().String(), client.ClientConfig())
+	if err != nil {
+		return err
+	}
+	conn.Close()
+	return nil
+}
+
+func TestCredentials(t *testing.T) {
+	creds, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := handshake(t, creds, creds); err != nil {
+		t.Errorf("expected handshake with matching credentials to succeed: %v", err)
+	}
+
+	// other has a different host key, so the client must reject the
+	// server, and a different client key, so the server must reject the
+	// client
+	if err := handshake(t, other, creds); err == nil {
+		t.Error("expected handshake with a different host key to fail")
+	}
+
+	if _, err := ssh.ParsePrivateKey(mustHostPrivateKey(t, creds)); err != nil {
+		t.Errorf("expected host private key to be parsable: %v", err)
+	}
+}
+
+func mustHostPrivateKey(t *testing.T, c *Credentials) []byte {
+	b, err := c.HostPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
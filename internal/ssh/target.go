@@ -0,0 +1,92 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the local targets reverse tunneled connections are sent to.
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Target is the local address a reverse tunneled port is sent to
+type Target struct {
+	// Network is either tcp or unix
+	Network string
+
+	// Address is host:port for tcp, or the path of the socket for unix
+	Address string
+}
+
+// ParseTarget parses a target, which is one of:
+//   - a port, e.g. 8080, which is sent to 127.0.0.1
+//   - host:port, optionally prefixed with tcp://, e.g. 172.17.0.2:8080
+//   - unix:// followed by the path of a Unix domain socket, e.g.
+//     unix:///tmp/app.sock
+func ParseTarget(s string) (Target, error) {
+	if path, ok := strings.CutPrefix(s, "unix://"); ok {
+		if path == "" {
+			return Target{}, fmt.Errorf("invalid target %q, expected a socket path", s)
+		}
+		return Target{Network: "unix", Address: path}, nil
+	}
+
+	addr := strings.TrimPrefix(s, "tcp://")
+	if _, err := strconv.ParseUint(addr, 10, 16); err == nil {
+		return Target{Network: "tcp", Address: net.JoinHostPort("127.0.0.1", addr)}, nil
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return Target{}, errors.Wrapf(err, "invalid target %q", s)
+	}
+	if host == "" {
+		return Target{}, fmt.Errorf("invalid target %q, expected a host", s)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return Target{}, fmt.Errorf("invalid target %q, expected a port", s)
+	}
+	return Target{Network: "tcp", Address: addr}, nil
+}
+
+// String returns the target in the format accepted by ParseTarget
+func (t Target) String() string {
+	if t.Network == "unix" {
+		return "unix://" + t.Address
+	}
+	return t.Address
+}
+
+// Dial connects to the target
+func (t Target) Dial(ctx context.Context) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	return dialer.DialContext(ctx, t.Network, t.Address)
+}
+
+// ParsePortMap parses a port in the format target:remote, or port if the
+// local and remote ports are the same. Since the remote is always a port,
+// the target may contain colons, e.g. unix:///tmp/app.sock:8080.
+func ParsePortMap(s string) (uint16, Target, error) {
+	target, remote := s, s
+	if i := strings.LastIndex(s, ":"); i != -1 {
+		target, remote = s[:i], s[i+1:]
+	}
+
+	remotePort, err := strconv.ParseUint(remote, 10, 16)
+	if err != nil {
+		return 0, Target{}, errors.Wrapf(err, "invalid port %q", s)
+	}
+
+	t, err := ParseTarget(target)
+	if err != nil {
+		return 0, Target{}, err
+	}
+
+	// nolint: gosec // Why: parsed with a bit size of 16
+	return uint16(remotePort), t, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package ssh.
+package ssh
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePortMap(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantRemote uint16
+		want       Target
+		wantErr    bool
+	}{
+		{in: "8080", wantRemote: 8080, want: Target{Network: "tcp", Address: "127.0.0.1:8080"}},
+		{in: "3000:8080", wantRemote: 8080, want: Target{Network: "tcp", Address: "127.0.0.1:3000"}},
+		{in: "172.17.0.2:80:8080", wantRemote: 8080, want: Target{Network: "tcp", Address: "172.17.0.2:80"}},
+		{in: "tcp://[::1]:80:8080", wantRemote: 8080, want: Target{Network: "tcp", Address: "[::1]:80"}},
+		{in: "unix:///tmp/app.sock:8080", wantRemote: 8080, want: Target{Network: "unix", Address: "/tmp/app.sock"}},
+		{in: "unix://:8080", wantErr: true},
+		{in: "localhost:8080:http", wantErr: true},
+		{in: "localhost:http:8080", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			remote, got, err := ParsePortMap(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePortMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if remote != tt.wantRemote {
+				t.Errorf("ParsePortMap() remote = %d, want %d", remote, tt.wantRemote)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParsePortMap() target mismatch (-want +got):\n%s", diff)
+			}
+
+			// targets must round trip, since they're passed around as strings
+			if parsed, err := ParseTarget(got.String()); err != nil || parsed != got {
+				t.Errorf("ParseTarget(%q) = %v, %v", got.String(), parsed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains helpers for running network configuration commands.
+package tun
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// runAll runs each of commands in order, stopping at the first one that
+// fails
+func runAll(commands [][]string) error {
+	for _, args := range commands {
+		//nolint:gosec // Why: arguments are constructed from parsed addresses
+		out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+		if err != nil {
+			return errors.Wrapf(err, "failed to run %q: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the packet devices used by the VPN.
+
+// Package tun implements the devices packets are sent over by the VPN:
+// TUN devices, which hand the packets routed to them to a process, and
+// stream devices, which carry packets over a stream such as an agent
+// session.
+package tun
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/netip"
+	"sync"
+)
+
+// MTU is the MTU of the TUN devices on both ends of the VPN. It's below
+// the MTU of most overlay networks, so that packets forwarded into the
+// pod network by the agent don't have to be fragmented.
+const MTU = 1380
+
+// This block contains the addresses of both ends of the VPN, they're
+// from the range reserved for benchmarking (RFC 2544) which clusters are
+// unlikely to use
+var (
+	// AgentAddress is the address of the agent's device
+	AgentAddress = netip.MustParseAddr("198.18.0.1")
+
+	// ClientAddress is the address of localizer's device, packets sent
+	// from it are masqueraded by the agent
+	ClientAddress = netip.MustParseAddr("198.18.0.2")
+)
+
+// maxPacketSize is the largest packet a Device can carry, which is the
+// largest possible IP packet
+const maxPacketSize = 65535
+
+// Device is a packet oriented io.ReadWriteCloser, every Read returns
+// a single IP packet and every Write sends one.
+type Device io.ReadWriteCloser
+
+// streamDevice is a Device that carries packets over a stream, each
+// prefixed by its length
+type streamDevice struct {
+	rwc io.ReadWriteCloser
+
+	// wmu ensures packets are written atomically
+	wmu sync.Mutex
+}
+
+// NewStreamDevice creates a Device that sends packets over rwc, e.g.
+// a yamux stream
+func NewStreamDevice(rwc io.ReadWriteCloser) Device {
+	return &streamDevice{rwc: rwc}
+}
+
+// Read implements io.Reader
+func (d *streamDevice) Read(b []byte) (int, error) {
+	var size uint16
+	if err := binary.Read(d.rwc, binary.BigEndian, &size); err != nil {
+		return 0, err
+	}
+
+	if int(size) > len(b) {
+		// the packet can't be returned, but the stream has to be kept in
+		// sync so skip it
+		if _, err := io.CopyN(io.Discard, d.rwc, int64(size)); err != nil {
+			return 0, err
+		}
+		return 0, io.ErrShortBuffer
+	}
+
+	return io.ReadFull(d.rwc, b[:size])
+}
+
+// Write implements io.Writer
+func (d *streamDevice) Write(b []byte) (int, error) {
+	if len(b) > maxPacketSize {
+		return 0, fmt.Errorf("packet of %d bytes is too large", len(b))
+	}
+
+	buf := make([]byte, 2+len(b))
+	binary.BigEndian.PutUint16(buf, uint16(len(b))) //nolint:gosec // Why: checked above
+	copy(buf[2:], b)
+
+	d.wmu.Lock()
+	defer d.wmu.Unlock()
+	if _, err := d.rwc.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close implements io.Closer
+func (d *streamDevice) Close() error {
+	return d.rwc.Close()
+}
+
+// Relay sends the packets read from a to b, and the packets read from b
+// to a, until either fails. The error of the first one to fail is
+// returned. Neither device is closed, so the other direction only stops
+// once it reads its next packet.
+func Relay(a, b Device) error {
+	errC := make(chan error, 2)
+	go func() { errC <- copyPackets(a, b) }()
+	go func() { errC <- copyPackets(b, a) }()
+	return <-errC
+}
+
+// copyPackets writes the packets read from src to dst until either fails
+func copyPackets(dst, src Device) error {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, err := src.Read(buf)
+		if err != nil {
+			if err == io.ErrShortBuffer {
+				continue
+			}
+			return err
+		}
+
+		if _, err := dst.Write(buf[:n]); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the darwin implementation of TUN devices.
+
+//go:build darwin
+
+package tun
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// This block contains the constants of utun devices, which aren't
+// exported by x/sys
+const (
+	sysprotoControl = 2
+	utunOptIfname   = 2
+	utunControlName = "com.apple.net.utun_control"
+)
+
+// TUN is a utun device, packets routed to it are read from it and
+// packets written to it are received by the kernel
+type TUN struct {
+	f    *os.File
+	name string
+}
+
+// Open creates a utun device, the kernel picks its name so name is
+// ignored. The device is removed, along with its routes, when it's
+// closed.
+func Open(_ string) (*TUN, error) {
+	fd, err := unix.Socket(unix.AF_SYSTEM, unix.SOCK_DGRAM, sysprotoControl)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create utun socket")
+	}
+
+	info := &unix.CtlInfo{}
+	copy(info.Name[:], utunControlName)
+	if err := unix.IoctlCtlInfo(fd, info); err != nil {
+		unix.Close(fd)
+		return nil, errors.Wrap(err, "failed to look up utun control")
+	}
+
+	// unit 0 lets the kernel pick the first free utun device
+	if err := unix.Connect(fd, &unix.SockaddrCtl{ID: info.Id, Unit: 0}); err != nil {
+		unix.Close(fd)
+		return nil, errors.Wrap(err, "failed to create utun device")
+	}
+
+	name, err := unix.GetsockoptString(fd, sysprotoControl, utunOptIfname)
+	if err != nil {
+		unix.Close(fd)
+		return nil, errors.Wrap(err, "failed to get name of utun device")
+	}
+
+	// non-blocking fds are registered with the runtime's poller, which
+	// allows Close to interrupt a blocked Read
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return nil, errors.Wrap(err, "failed to configure utun device")
+	}
+
+	return &TUN{f: os.NewFile(uintptr(fd), name), name: name}, nil
+}
+
+// Name returns the name of the device, e.g. utun3
+func (t *TUN) Name() string {
+	return t.name
+}
+
+// Read implements io.Reader, packets are prefixed with their address
+// family which is stripped
+func (t *TUN) Read(b []byte) (int, error) {
+	buf := make([]byte, len(b)+4)
+	n, err := t.f.Read(buf)
+	if err != nil {
+		return 0, err
+	}
+	if n < 4 {
+		return 0, fmt.Errorf("short read of %d bytes from utun device", n)
+	}
+	return copy(b, buf[4:n]), nil
+}
+
+// Write implements io.Writer
+func (t *TUN) Write(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	family := uint32(unix.AF_INET)
+	if b[0]>>4 == 6 {
+		family = unix.AF_INET6
+	}
+
+	buf := make([]byte, len(b)+4)
+	binary.BigEndian.PutUint32(buf, family)
+	copy(buf[4:], b)
+	if _, err := t.f.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close implements io.Closer
+func (t *TUN) Close() error {
+	return t.f.Close()
+}
+
+// Configure assigns local to the device, with peer being the address of
+// the other end of the VPN, brings it up, and routes routes through it.
+func (t *TUN) Configure(local, peer netip.Addr, routes []netip.Prefix) error {
+	family := "inet"
+	if local.Is6() {
+		family = "inet6"
+	}
+
+	commands := [][]string{
+		{"ifconfig", t.name, family, local.String(), peer.String(), "mtu", strconv.Itoa(MTU), "up"},
+	}
+	for _, r := range routes {
+		family := "-inet"
+		if r.Addr().Is6() {
+			family = "-inet6"
+		}
+		commands = append(commands, []string{"route", "-q", "-n", "add", family, "-net", r.String(), "-interface", t.name})
+	}
+
+	return runAll(commands)
+}
+
+// EnableNAT isn't supported on darwin, it's only used by the agent
+func (t *TUN) EnableNAT(_ netip.Prefix) error {
+	return fmt.Errorf("nat is not supported on darwin")
+}
+
+// DisableNAT isn't supported on darwin, it's only used by the agent
+func (t *TUN) DisableNAT(_ netip.Prefix) error {
+	return fmt.Errorf("nat is not supported on darwin")
+}
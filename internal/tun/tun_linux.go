@@ -0,0 +1,107 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the linux implementation of TUN devices.
+
+//go:build linux
+
+package tun
+
+import (
+	"net/netip"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// cloneDevice is opened to create TUN devices
+const cloneDevice = "/dev/net/tun"
+
+// TUN is a TUN device, packets routed to it are read from it and
+// packets written to it are received by the kernel
+type TUN struct {
+	*os.File
+
+	name string
+}
+
+// Open creates a TUN device named name, a %d in name is replaced with
+// a number by the kernel. The device is removed, along with its routes,
+// when it's closed.
+func Open(name string) (*TUN, error) {
+	fd, err := unix.Open(cloneDevice, unix.O_RDWR|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", cloneDevice)
+	}
+
+	ifr, err := unix.NewIfreq(name)
+	if err != nil {
+		unix.Close(fd)
+		return nil, errors.Wrapf(err, "invalid device name %q", name)
+	}
+	ifr.SetUint16(unix.IFF_TUN | unix.IFF_NO_PI)
+
+	if err := unix.IoctlIfreq(fd, unix.TUNSETIFF, ifr); err != nil {
+		unix.Close(fd)
+		return nil, errors.Wrap(err, "failed to create tun device")
+	}
+
+	// non-blocking fds are registered with the runtime's poller, which
+	// allows Close to interrupt a blocked Read
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return nil, errors.Wrap(err, "failed to configure tun device")
+	}
+
+	return &TUN{File: os.NewFile(uintptr(fd), cloneDevice), name: ifr.Name()}, nil
+}
+
+// Name returns the name of the device, e.g. tun0
+func (t *TUN) Name() string {
+	return t.name
+}
+
+// Configure assigns local to the device, with peer being the address of
+// the other end of the VPN, brings it up, and routes routes through it.
+func (t *TUN) Configure(local, peer netip.Addr, routes []netip.Prefix) error {
+	commands := [][]string{
+		{"ip", "addr", "add", local.String(), "peer", peer.String(), "dev", t.name},
+		{"ip", "link", "set", "dev", t.name, "mtu", strconv.Itoa(MTU), "up"},
+	}
+	for _, r := range routes {
+		commands = append(commands, []string{"ip", "route", "replace", r.String(), "dev", t.name})
+	}
+
+	return runAll(commands)
+}
+
+// EnableNAT forwards the packets written to the device from source to
+// the rest of the network, masquerading them as coming from this host.
+// The rule is removed again by DisableNAT.
+func (t *TUN) EnableNAT(source netip.Prefix) error {
+	sysctl := "/proc/sys/net/ipv4/ip_forward"
+	if source.Addr().Is6() {
+		sysctl = "/proc/sys/net/ipv6/conf/all/forwarding"
+	}
+	if err := os.WriteFile(sysctl, []byte("1"), 0o644); err != nil { //nolint:gosec // Why: sysctls are world readable
+		return errors.Wrap(err, "failed to enable ip forwarding")
+	}
+
+	return runAll([][]string{natArgs(t.name, source, "-A")})
+}
+
+// DisableNAT removes the rule added by EnableNAT
+func (t *TUN) DisableNAT(source netip.Prefix) error {
+	return runAll([][]string{natArgs(t.name, source, "-D")})
+}
+
+// natArgs returns the iptables command that adds, or deletes, the rule
+// masquerading the packets sent from source through dev
+func natArgs(dev string, source netip.Prefix, op string) []string {
+	cmd := "iptables"
+	if source.Addr().Is6() {
+		cmd = "ip6tables"
+	}
+	return []string{cmd, "-t", "nat", op, "POSTROUTING", "-s", source.String(), "!", "-o", dev, "-j", "MASQUERADE"}
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the TUN device stub for unsupported platforms.
+
+//go:build !linux && !darwin
+
+package tun
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"runtime"
+)
+
+// TUN is a TUN device, they're not supported on this platform
+type TUN struct {
+	io.ReadWriteCloser
+}
+
+// Open returns an error, TUN devices aren't supported on this platform
+func Open(_ string) (*TUN, error) {
+	return nil, fmt.Errorf("tun devices are not supported on %s", runtime.GOOS)
+}
+
+// Name returns the name of the device
+func (t *TUN) Name() string {
+	return ""
+}
+
+// Configure returns an error, TUN devices aren't supported on this
+// platform
+func (t *TUN) Configure(_, _ netip.Addr, _ []netip.Prefix) error {
+	return fmt.Errorf("tun devices are not supported on %s", runtime.GOOS)
+}
+
+// EnableNAT returns an error, TUN devices aren't supported on this
+// platform
+func (t *TUN) EnableNAT(_ netip.Prefix) error {
+	return fmt.Errorf("tun devices are not supported on %s", runtime.GOOS)
+}
+
+// DisableNAT returns an error, TUN devices aren't supported on this
+// platform
+func (t *TUN) DisableNAT(_ netip.Prefix) error {
+	return fmt.Errorf("tun devices are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains helpers for discovering the CIDRs of a cluster.
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// cidrPattern matches the CIDRs in an error message
+var cidrPattern = regexp.MustCompile(`[0-9a-fA-F.:]+/[0-9]+`)
+
+// ParseCIDRs parses a list of CIDRs, e.g. the values of --vpn-cidr
+func ParseCIDRs(values []string) ([]netip.Prefix, error) {
+	cidrs := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cidr %q", v)
+		}
+		cidrs = append(cidrs, prefix.Masked())
+	}
+	return cidrs, nil
+}
+
+// DiscoverCIDRs returns the service and pod CIDRs of the cluster. Pod
+// CIDRs are read from the nodes, which isn't set by every network plugin,
+// and service CIDRs from the ServiceCIDR objects of the cluster or, on
+// older clusters, from the error returned when creating a service with
+// an invalid ClusterIP in namespace.
+func DiscoverCIDRs(ctx context.Context, k kubernetes.Interface, namespace string) ([]netip.Prefix, error) {
+	nodes, err := k.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+
+	values := make([]string, 0)
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if len(node.Spec.PodCIDRs) == 0 && node.Spec.PodCIDR != "" {
+			values = append(values, node.Spec.PodCIDR)
+		}
+		values = append(values, node.Spec.PodCIDRs...)
+	}
+
+	serviceCIDRs, err := discoverServiceCIDRs(ctx, k, namespace)
+	if err != nil {
+		return nil, err
+	}
+	values = append(values, serviceCIDRs...)
+
+	cidrs, err := ParseCIDRs(values)
+	if err != nil {
+		return nil, err
+	}
+	if len(cidrs) == 0 {
+		return nil, fmt.Errorf("no cidrs were found")
+	}
+
+	slices.SortFunc(cidrs, func(a, b netip.Prefix) int {
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c
+		}
+		return a.Bits() - b.Bits()
+	})
+	return slices.Compact(cidrs), nil
+}
+
+// discoverServiceCIDRs returns the service CIDRs of the cluster
+func discoverServiceCIDRs(ctx context.Context, k kubernetes.Interface, namespace string) ([]string, error) {
+	// ServiceCIDRs are only available since Kubernetes 1.31, errors are
+	// ignored in favor of the fallback below
+	list, err := k.NetworkingV1beta1().ServiceCIDRs().List(ctx, metav1.ListOptions{})
+	if err == nil && len(list.Items) != 0 {
+		cidrs := make([]string, 0, len(list.Items))
+		for i := range list.Items {
+			cidrs = append(cidrs, list.Items[i].Spec.CIDRs...)
+		}
+		return cidrs, nil
+	}
+
+	// The API server rejects a ClusterIP outside of the service CIDR with
+	// an error containing the valid range, e.g. "The range of valid IPs is
+	// 10.96.0.0/12". Nothing is created, since it's a dry run.
+	_, err = k.CoreV1().Services(namespace).Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "localizer-vpn-cidr"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "1.1.1.1",
+			Ports:     []corev1.ServicePort{{Port: 443}},
+		},
+	}, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err == nil {
+		return nil, fmt.Errorf("failed to determine service cidr, service with invalid ClusterIP was accepted")
+	}
+
+	cidrs := make([]string, 0)
+	for _, match := range cidrPattern.FindAllString(err.Error(), -1) {
+		if _, perr := netip.ParsePrefix(match); perr == nil {
+			cidrs = append(cidrs, match)
+		}
+	}
+	if len(cidrs) == 0 {
+		return nil, errors.Wrap(err, "failed to determine service cidr")
+	}
+	return cidrs, nil
+}
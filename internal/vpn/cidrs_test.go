@@ -0,0 +1,83 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file has the package vpn.
+package vpn
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDiscoverCIDRs(t *testing.T) {
+	node := func(name string, cidrs ...string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{PodCIDR: cidrs[0], PodCIDRs: cidrs},
+		}
+	}
+
+	t.Run("service cidrs", func(t *testing.T) {
+		k := fake.NewClientset(
+			node("a", "10.244.0.0/24", "fd00:10:244::/64"),
+			node("b", "10.244.1.0/24"),
+			&networkingv1beta1.ServiceCIDR{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubernetes"},
+				Spec:       networkingv1beta1.ServiceCIDRSpec{CIDRs: []string{"10.96.0.0/12"}},
+			},
+		)
+
+		got, err := DiscoverCIDRs(context.Background(), k, "default")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []netip.Prefix{
+			netip.MustParsePrefix("10.96.0.0/12"),
+			netip.MustParsePrefix("10.244.0.0/24"),
+			netip.MustParsePrefix("10.244.1.0/24"),
+			netip.MustParsePrefix("fd00:10:244::/64"),
+		}
+		if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); diff != "" {
+			t.Errorf("unexpected cidrs (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid service error", func(t *testing.T) {
+		k := fake.NewClientset(node("a", "10.244.0.0/24"))
+		k.PrependReactor("create", "services", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, kerrors.NewInvalid(corev1.SchemeGroupVersion.WithKind("Service").GroupKind(), "localizer-vpn-cidr", nil)
+		})
+
+		_, err := DiscoverCIDRs(context.Background(), k, "default")
+		if err == nil {
+			t.Fatal("expected an error without a range in the error")
+		}
+
+		k = fake.NewClientset(node("a", "10.244.0.0/24"))
+		k.PrependReactor("create", "services", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, kerrors.NewBadRequest(`Service "localizer-vpn-cidr" is invalid: spec.clusterIPs: ` +
+				`Invalid value: []string{"1.1.1.1"}: failed to allocate IP 1.1.1.1: provided IP (1.1.1.1) is not in the ` +
+				`valid range. The range of valid IPs is 10.96.0.0/12`)
+		})
+
+		got, err := DiscoverCIDRs(context.Background(), k, "default")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []netip.Prefix{netip.MustParsePrefix("10.96.0.0/12"), netip.MustParsePrefix("10.244.0.0/24")}
+		if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); diff != "" {
+			t.Errorf("unexpected cidrs (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the pod running the VPN agent.
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/agent"
+	"github.com/getoutreach/localizer/internal/expose"
+	"github.com/getoutreach/localizer/internal/ssh"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// ImageRepository is the image repository of the VPN agent, which
+	// is tagged with the version of localizer
+	ImageRepository = "ghcr.io/getoutreach/localizer-vpn"
+
+	// PodLabel is set on every VPN pod and secret
+	PodLabel = "localizer.jaredallard.github.com/vpn"
+
+	// agentPort is the port the agent accepts sessions on
+	agentPort = 2222
+
+	// keysDir is where the agent reads its keys from
+	keysDir = "/etc/localizer/keys"
+
+	// podReadyTimeout is how long to wait for the pod to become ready
+	podReadyTimeout = 2 * time.Minute
+)
+
+// Image returns the VPN agent image matching this version of localizer,
+// development builds use the latest image.
+func Image() string {
+	return expose.ImageForVersion(ImageRepository)
+}
+
+// pod is a pod running the VPN agent, along with the secret containing
+// its credentials
+type pod struct {
+	k   kubernetes.Interface
+	log logrus.FieldLogger
+
+	po     *corev1.Pod
+	secret *corev1.Secret
+}
+
+// createPod creates a VPN agent pod that only accepts creds, and waits
+// for it to become ready
+func (v *VPN) createPod(ctx context.Context, creds *ssh.Credentials) (*pod, error) {
+	hostKey, err := creds.HostPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pod{k: v.k, log: v.log}
+	p.secret, err = v.k.CoreV1().Secrets(v.opts.Namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    v.opts.Namespace,
+			GenerateName: "localizer-vpn-",
+			Labels:       map[string]string{PodLabel: "true"},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			agent.AuthorizedKeysFile: []byte(creds.AuthorizedKey() + "\n"),
+			agent.HostKeyFile:        hostKey,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create credentials secret")
+	}
+
+	p.po, err = v.k.CoreV1().Pods(v.opts.Namespace).Create(ctx, v.podSpec(p.secret), metav1.CreateOptions{})
+	if err != nil {
+		p.delete()
+		return nil, errors.Wrap(err, "failed to create pod")
+	}
+	v.log.Infof("created vpn pod %s", p.po.Name)
+
+	// the secret is garbage collected with the pod, even if localizer
+	// doesn't get a chance to clean it up
+	p.secret.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       p.po.Name,
+		UID:        p.po.UID,
+	}}
+	if _, err := v.k.CoreV1().Secrets(v.opts.Namespace).Update(ctx, p.secret, metav1.UpdateOptions{}); err != nil {
+		v.log.WithError(err).Warn("failed to set owner of credentials secret")
+	}
+
+	v.log.Info("waiting for vpn pod to be ready ...")
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, podReadyTimeout, true, func(ctx context.Context) (bool, error) {
+		po, err := v.k.CoreV1().Pods(v.opts.Namespace).Get(ctx, p.po.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		p.po = po
+
+		for _, cond := range po.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		p.delete()
+		return nil, errors.Wrap(err, "failed to wait for vpn pod to be ready")
+	}
+
+	return p, nil
+}
+
+// podSpec returns the VPN agent pod, reading its keys from secret
+func (v *VPN) podSpec(secret *corev1.Secret) *corev1.Pod {
+	image := v.opts.Image
+	if image == "" {
+		image = Image()
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    v.opts.Namespace,
+			GenerateName: "localizer-vpn-",
+			Labels:       map[string]string{PodLabel: "true"},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyOnFailure,
+			Containers: []corev1.Container{{
+				Name:            "default",
+				Image:           image,
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				Args:            []string{"vpn", "--listen", fmt.Sprintf(":%d", agentPort), "--keys-dir", keysDir},
+				ReadinessProbe: &corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						TCPSocket: &corev1.TCPSocketAction{
+							Port: intstr.FromInt(agentPort),
+						},
+					},
+				},
+				// creating a TUN device, enabling forwarding and adding
+				// the NAT rule require a privileged container
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: ptr.To(true),
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "keys", MountPath: keysDir, ReadOnly: true},
+				},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "keys",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName:  secret.Name,
+						DefaultMode: ptr.To(int32(0o400)),
+					},
+				},
+			}},
+		},
+	}
+}
+
+// running returns true if the pod still exists and is running
+func (p *pod) running(ctx context.Context) bool {
+	po, err := p.k.CoreV1().Pods(p.po.Namespace).Get(ctx, p.po.Name, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	return po.DeletionTimestamp == nil && po.Status.Phase == corev1.PodRunning
+}
+
+// delete deletes the pod and its secret
+func (p *pod) delete() {
+	// we don't use the VPN's context since it's usually canceled by now
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if p.po != nil {
+		err := p.k.CoreV1().Pods(p.po.Namespace).Delete(ctx, p.po.Name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			p.log.WithError(err).Warn("failed to delete vpn pod")
+		}
+	}
+
+	err := p.k.CoreV1().Secrets(p.secret.Namespace).Delete(ctx, p.secret.Name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		p.log.WithError(err).Warn("failed to delete vpn credentials secret")
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 Outreach Corporation. Licensed under the Apache License 2.0.
+
+// Description: This file contains the VPN, which routes cluster traffic through an agent.
+
+// Package vpn implements localizer's VPN mode. Instead of port-forwarding
+// every service, the cluster's service and pod CIDRs are routed to a TUN
+// device, whose packets are relayed to an agent running in the cluster
+// that forwards them into the pod network. ClusterIPs and pod IPs then
+// work natively, on any port.
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"time"
+
+	"github.com/getoutreach/localizer/internal/agent"
+	"github.com/getoutreach/localizer/internal/kube"
+	"github.com/getoutreach/localizer/internal/ssh"
+	"github.com/getoutreach/localizer/internal/tun"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// reconnectInterval is how long to wait before reconnecting to the agent
+// after the session failed
+const reconnectInterval = 5 * time.Second
+
+// Options configures a VPN
+type Options struct {
+	// Namespace is the namespace the agent pod is created in
+	Namespace string
+
+	// Image is the image of the agent, defaults to Image()
+	Image string
+
+	// CIDRs are the prefixes routed through the VPN, they're discovered
+	// from the cluster if empty, see DiscoverCIDRs. Only IPv4 is routed,
+	// IPv6 prefixes are ignored.
+	CIDRs []netip.Prefix
+
+	// Transport is the protocol the port-forward to the agent is
+	// tunneled over
+	Transport kube.PortForwardTransport
+}
+
+// VPN routes traffic for the cluster through an agent pod
+type VPN struct {
+	k    kubernetes.Interface
+	rest *rest.Config
+	log  logrus.FieldLogger
+	opts *Options
+}
+
+// New creates a new VPN, it isn't started until Start is called
+func New(k kubernetes.Interface, kconf *rest.Config, log logrus.FieldLogger, opts *Options) *VPN {
+	return &VPN{k: k, rest: kconf, log: log.WithField("component", "vpn"), opts: opts}
+}
+
+// Start creates the TUN device and agent pod, and relays packets between
+// them until the context is canceled. If the session with the agent
+// fails it's reconnected, and the pod is recreated if it's gone. The pod
+// is deleted, and the routes removed, before Start returns.
+func (v *VPN) Start(ctx context.Context) error {
+	cidrs := v.opts.CIDRs
+	if len(cidrs) == 0 {
+		var err error
+		cidrs, err = DiscoverCIDRs(ctx, v.k, v.opts.Namespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to discover cluster cidrs, set them with --vpn-cidr")
+		}
+	}
+
+	// the tun device, and the agent's NAT, only have IPv4 addresses
+	cidrs = slices.DeleteFunc(slices.Clone(cidrs), func(prefix netip.Prefix) bool {
+		if prefix.Addr().Is6() {
+			v.log.Warnf("not routing %s, ipv6 isn't supported by the vpn", prefix)
+			return true
+		}
+		return false
+	})
+	if len(cidrs) == 0 {
+		return fmt.Errorf("no ipv4 cidrs to route through the vpn")
+	}
+
+	dev, err := tun.Open("localizer%d")
+	if err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	if err := dev.Configure(tun.ClientAddress, tun.AgentAddress, cidrs); err != nil {
+		return errors.Wrap(err, "failed to configure tun device")
+	}
+	v.log.Infof("routing %v through %s", cidrs, dev.Name())
+
+	// credentials are shared by every pod created by this VPN
+	creds, err := ssh.GenerateCredentials()
+	if err != nil {
+		return err
+	}
+
+	var p *pod
+	defer func() {
+		if p != nil {
+			p.delete()
+		}
+	}()
+
+	for {
+		if p == nil {
+			p, err = v.createPod(ctx, creds)
+		}
+		if err == nil {
+			err = v.connect(ctx, p, dev, creds)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		v.log.WithError(err).Warn("vpn session failed, reconnecting")
+
+		if p != nil && !p.running(ctx) {
+			v.log.Info("vpn pod is gone, recreating it")
+			p.delete()
+			p = nil
+		}
+
+		select {
+		case <-time.After(reconnectInterval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// connect port-forwards to the agent in p and relays packets to it from
+// dev until the session fails
+func (v *VPN) connect(ctx context.Context, p *pod, dev tun.Device, creds *ssh.Credentials) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fw, err := kube.CreatePortForward(ctx, v.k.CoreV1().RESTClient(), v.rest, p.po, "127.0.0.1",
+		[]string{fmt.Sprintf("0:%d", agentPort)}, v.opts.Transport)
+	if err != nil {
+		return errors.Wrap(err, "failed to create port-forward")
+	}
+	fw.Ready = make(chan struct{})
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- fw.ForwardPorts()
+	}()
+
+	select {
+	case <-fw.Ready:
+	case err := <-errC:
+		return errors.Wrap(err, "failed to port-forward to vpn pod")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ports, err := fw.GetPorts()
+	if err != nil {
+		return errors.Wrap(err, "failed to get local port of port-forward")
+	}
+	if len(ports) == 0 {
+		return fmt.Errorf("port-forward to vpn pod has no ports")
+	}
+
+	// the session is closed by yamux's keep-alives when the port-forward
+	// dies, which returns from Start
+	return agent.NewVPNClient(v.log, "127.0.0.1", int(ports[0].Local), creds).Start(ctx, dev)
+}
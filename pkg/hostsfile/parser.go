@@ -355,3 +355,69 @@ func (f *File) RemoveAddress(ipAddress string) error {
 	delete(f.hostsFile, ipAddress)
 	return nil
 }
+
+// AppendHosts adds the given hosts to the line for the specified IP,
+// keeping any hosts that already resolve to it. This is useful when
+// more than one caller shares the same IP address.
+func (f *File) AppendHosts(ipAddress string, hosts []string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, h := range hosts {
+		if !govalidator.IsDNSName(h) {
+			return fmt.Errorf("'%s' is not a valid dns name", h)
+		}
+	}
+
+	line, ok := f.hostsFile[ipAddress]
+	if !ok {
+		f.hostsFile[ipAddress] = &HostLine{Addresses: hosts}
+		return nil
+	}
+
+	existing := make(map[string]bool, len(line.Addresses))
+	for _, h := range line.Addresses {
+		existing[h] = true
+	}
+
+	for _, h := range hosts {
+		if !existing[h] {
+			line.Addresses = append(line.Addresses, h)
+			existing[h] = true
+		}
+	}
+
+	return nil
+}
+
+// RemoveHosts removes the given hosts from the line for the specified
+// IP. If no hosts are left for the IP, then the address is removed.
+func (f *File) RemoveHosts(ipAddress string, hosts []string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	line, ok := f.hostsFile[ipAddress]
+	if !ok {
+		return nil
+	}
+
+	remove := make(map[string]bool, len(hosts))
+	for _, h := range hosts {
+		remove[h] = true
+	}
+
+	kept := make([]string, 0, len(line.Addresses))
+	for _, h := range line.Addresses {
+		if !remove[h] {
+			kept = append(kept, h)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(f.hostsFile, ipAddress)
+		return nil
+	}
+
+	line.Addresses = kept
+	return nil
+}
@@ -285,3 +285,92 @@ func TestFile_RemoveHosts(t *testing.T) {
 		t.Error("expected: ", cmp.Diff(f.contents, b))
 	}
 }
+
+func TestFile_AddHostsIPv6(t *testing.T) {
+	f := NewWithContents("", []byte("127.0.0.1 localhost"))
+	f.clock = clock.NewMock()
+
+	err := f.AddHosts("fd00::2", []string{"i-am-a-hostname"})
+	if err != nil {
+		t.Error(errors.Wrap(err, "failed to add an ipv6 address"))
+	}
+
+	err = f.AddHosts("127.0.1.1", []string{"i-am-a-hostname"})
+	if err != nil {
+		t.Error(errors.Wrap(err, "failed to add an ipv4 address"))
+	}
+
+	b, err := f.Marshal(context.Background())
+	if err != nil {
+		t.Error(errors.Wrap(err, "failed to marshal hosts file"))
+	}
+
+	// IPv4 addresses should always sort before IPv6 addresses
+	expected := []byte("127.0.0.1 localhost\n###start-hostfile\n" +
+		"###{\"blockName\":\"localizer\",\"last_modified_at\":\"1970-01-01T00:00:00Z\"}\n" +
+		"127.0.1.1 i-am-a-hostname\nfd00::2 i-am-a-hostname\n###end-hostfile")
+	if !reflect.DeepEqual(expected, b) {
+		t.Error("expected: ", cmp.Diff(string(expected), string(b)))
+	}
+
+	f = NewWithContents("", b)
+	err = f.Load(context.Background())
+	if err != nil {
+		t.Error(errors.Wrap(err, "failed to load hosts"))
+	}
+
+	if _, ok := f.hostsFile["fd00::2"]; !ok {
+		t.Error("expected ipv6 address to be loaded from hosts file")
+	}
+}
+
+func TestFile_AppendAndRemoveHosts(t *testing.T) {
+	f := NewWithContents("", []byte{})
+
+	err := f.AppendHosts("127.0.0.1", []string{"service-a", "service-a.default"})
+	if err != nil {
+		t.Error(errors.Wrap(err, "failed to append hosts"))
+	}
+
+	err = f.AppendHosts("127.0.0.1", []string{"service-b", "service-a"})
+	if err != nil {
+		t.Error(errors.Wrap(err, "failed to append hosts to an existing address"))
+	}
+
+	err = f.AppendHosts("127.0.0.1", []string{"not a hostname"})
+	if err == nil {
+		t.Error("allowed a invalid dns name")
+	}
+
+	expected := map[string]*HostLine{
+		"127.0.0.1": {
+			Addresses: []string{"service-a", "service-a.default", "service-b"},
+		},
+	}
+	if !reflect.DeepEqual(f.hostsFile, expected) {
+		t.Error("expected: ", cmp.Diff(expected, f.hostsFile))
+	}
+
+	err = f.RemoveHosts("127.0.0.1", []string{"service-a", "service-a.default"})
+	if err != nil {
+		t.Error(errors.Wrap(err, "failed to remove hosts"))
+	}
+
+	expected = map[string]*HostLine{
+		"127.0.0.1": {
+			Addresses: []string{"service-b"},
+		},
+	}
+	if !reflect.DeepEqual(f.hostsFile, expected) {
+		t.Error("expected: ", cmp.Diff(expected, f.hostsFile))
+	}
+
+	err = f.RemoveHosts("127.0.0.1", []string{"service-b"})
+	if err != nil {
+		t.Error(errors.Wrap(err, "failed to remove hosts"))
+	}
+
+	if len(f.hostsFile) != 0 {
+		t.Error("expected address to be removed once it had no hosts left")
+	}
+}
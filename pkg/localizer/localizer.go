@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/getoutreach/localizer/api"
 	"github.com/pkg/errors"
@@ -20,9 +21,48 @@ import (
 // on.
 const Socket = "/var/run/localizer.sock"
 
+// SocketEnvVar is the environment variable that overrides Socket and
+// UserSocketPath.
+const SocketEnvVar = "LOCALIZER_SOCKET"
+
+// SocketPath returns the socket the localizer server is listening on,
+// which is SocketEnvVar when it's set. Otherwise, users other than root
+// use the socket of a server they run themselves, see UserSocketPath, if
+// it exists, and Socket if it doesn't.
+func SocketPath() string {
+	if path := os.Getenv(SocketEnvVar); path != "" {
+		return path
+	}
+
+	if os.Geteuid() != 0 {
+		if path, err := UserSocketPath(); err == nil {
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+
+	return Socket
+}
+
+// UserSocketPath returns the socket of a localizer server run without
+// root, e.g. in proxy mode, which is private to the user running it. It's
+// in $XDG_RUNTIME_DIR, or ~/.localizer when that isn't set.
+func UserSocketPath() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "localizer.sock"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get home directory")
+	}
+	return filepath.Join(home, ".localizer", "localizer.sock"), nil
+}
+
 // IsRunning checks to see if the localizer socket exists.
 func IsRunning() bool {
-	if _, err := os.Stat(Socket); err != nil {
+	if _, err := os.Stat(SocketPath()); err != nil {
 		return false
 	}
 
@@ -34,7 +74,7 @@ func IsRunning() bool {
 func Connect(ctx context.Context, opts ...grpc.DialOption) (client api.LocalizerServiceClient,
 	closer func(), err error) {
 	// nolint: staticcheck // Why: we are not upgrading to the new grpc API yet.
-	clientConn, err := grpc.DialContext(ctx, fmt.Sprintf("unix://%s", Socket), opts...)
+	clientConn, err := grpc.DialContext(ctx, fmt.Sprintf("unix://%s", SocketPath()), opts...)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "dial localizer")
 	}
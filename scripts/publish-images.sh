@@ -0,0 +1,36 @@
+#!/usr/bin/env bash
+# Builds and pushes the images of the pods created by localizer, which
+# are tagged with the version of localizer they're pulled by (see
+# expose.ImageForVersion). Releases are also tagged latest, which is
+# what development builds pull. Run from CI for release tags, after
+# logging in to the registry:
+#
+#   ./scripts/publish-images.sh v1.2.3
+set -euo pipefail
+
+version="${1:?usage: $0 <version>}"
+registry="${REGISTRY:-ghcr.io/getoutreach}"
+platforms="${PLATFORMS:-linux/amd64,linux/arm64}"
+
+DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" >/dev/null 2>&1 && pwd)"
+cd "$DIR/.."
+
+# build <image> <context> builds deployments/<image>/Dockerfile and
+# pushes it
+build() {
+  local image="$1" context="$2"
+  local tags=(--tag "$registry/$image:$version")
+  # pre-releases aren't pulled by development builds
+  if [[ $version != *-* ]]; then
+    tags+=(--tag "$registry/$image:latest")
+  fi
+
+  echo "Publishing $registry/$image:$version"
+  docker buildx build --push --platform "$platforms" \
+    --build-arg VERSION="$version" "${tags[@]}" \
+    --file "deployments/$image/Dockerfile" "$context"
+}
+
+build localizer-agent .
+build localizer-tunnel deployments/localizer-tunnel
+build localizer-vpn .